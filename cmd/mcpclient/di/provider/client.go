@@ -0,0 +1,10 @@
+package provider
+
+import (
+	"github.com/google/wire"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/client"
+)
+
+var ClientSet = wire.NewSet(
+	client.ProvideClient,
+)
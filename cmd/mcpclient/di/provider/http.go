@@ -0,0 +1,10 @@
+package provider
+
+import (
+	"github.com/google/wire"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/interfaces/http"
+)
+
+var HTTPSet = wire.NewSet(
+	http.NewHTTPHandler,
+)
@@ -2,10 +2,15 @@ package provider
 
 import (
 	"github.com/google/wire"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/infrastructure"
 )
 
 var InfrastructureSet = wire.NewSet(
-	infrastructure.NewMCPRepositoryImpl,
 	infrastructure.NewConfigRepositoryImpl,
+	wire.Bind(new(repository.IFConfigRepository), new(*infrastructure.ConfigRepositoryImpl)),
+	infrastructure.NewSecretRepositoryImpl,
+	wire.Bind(new(repository.IFSecretRepository), new(*infrastructure.SecretRepositoryImpl)),
+	infrastructure.NewAuditRepositoryImpl,
+	wire.Bind(new(repository.IFAuditRepository), new(*infrastructure.AuditRepositoryImpl)),
 )
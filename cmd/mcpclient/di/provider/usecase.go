@@ -7,5 +7,9 @@ import (
 
 var UsecaseSet = wire.NewSet(
 	usecase.NewMCPUsecase,
+	usecase.NewAuditUsecase,
+	usecase.NewPolicyUsecase,
+	usecase.NewApprovalUsecase,
 	usecase.NewConfigUsecase,
+	usecase.NewServerPool,
 )
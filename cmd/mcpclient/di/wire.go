@@ -11,8 +11,10 @@ import (
 func InitializeCLIHandler(configPath string) *provider.CliHandler {
 	wire.Build(
 		provider.InfrastructureSet,
+		provider.ClientSet,
 		provider.UsecaseSet,
 		provider.MessageSet,
+		provider.HTTPSet,
 		provider.CLISet,
 	)
 	return nil
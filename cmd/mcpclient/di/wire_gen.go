@@ -7,8 +7,10 @@
 package di
 
 import (
+	"github.com/t-yamakoshi/go-mcp-client/pkg/client"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/infrastructure"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/interfaces/cli"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/interfaces/http"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/interfaces/message"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/usecase"
 )
@@ -17,10 +19,17 @@ import (
 
 func InitializeCLIHandler(configPath string) *cli.CliHandler {
 	configRepositoryImpl := infrastructure.NewConfigRepositoryImpl(configPath)
-	mcpRepositoryImpl := infrastructure.NewMCPRepositoryImpl()
-	mcpUsecase := usecase.NewMCPUsecase(configRepositoryImpl, mcpRepositoryImpl)
-	configUsecase := usecase.NewConfigUsecase(configRepositoryImpl)
+	clientClient := client.ProvideClient()
+	auditRepositoryImpl := infrastructure.NewAuditRepositoryImpl()
+	auditUsecase := usecase.NewAuditUsecase(auditRepositoryImpl)
+	policyUsecase := usecase.NewPolicyUsecase()
+	approvalUsecase := usecase.NewApprovalUsecase()
+	mcpUsecase := usecase.NewMCPUsecase(configRepositoryImpl, clientClient, auditUsecase, policyUsecase, approvalUsecase)
+	secretRepositoryImpl := infrastructure.NewSecretRepositoryImpl()
+	configUsecase := usecase.NewConfigUsecase(configRepositoryImpl, secretRepositoryImpl)
+	serverPool := usecase.NewServerPool(configRepositoryImpl, mcpUsecase)
 	messageHandler := message.NewMessageHandler()
-	cliHandler := cli.NewCLIHandler(mcpUsecase, configUsecase, messageHandler)
+	httpHandler := http.NewHTTPHandler(mcpUsecase, configUsecase, approvalUsecase, serverPool)
+	cliHandler := cli.NewCLIHandler(mcpUsecase, configUsecase, auditUsecase, policyUsecase, approvalUsecase, serverPool, messageHandler, httpHandler)
 	return cliHandler
 }
@@ -1,14 +1,26 @@
 package main
 
 import (
-	"log"
+	"log/slog"
+	"os"
 
 	"github.com/t-yamakoshi/go-mcp-client/cmd/mcpclient/di"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/interfaces/cli"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
 )
 
 func main() {
+	// Secrets resolved from the configuration never reach the log
+	logging.Configure(logging.Options{Output: config.DefaultRedactor.Writer(os.Stderr)})
+
 	cliHandler := di.InitializeCLIHandler("config.json")
 	if err := cliHandler.Run(); err != nil {
-		log.Fatalf("Application failed: %v", err)
+		if code := cli.ExitCode(err); code != cli.ExitOK {
+			if !cli.Reported(err) {
+				slog.Error("application failed", "error", err)
+			}
+			os.Exit(code)
+		}
 	}
 }
@@ -0,0 +1,57 @@
+// Command client shows how to embed the MCP client in another Go program
+// without the CLI, configuration files or Wire.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/client"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+func main() {
+	serverURL := flag.String("server", "ws://localhost:3000", "MCP server URL")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	c, err := client.Dial(ctx, *serverURL,
+		client.WithClientInfo(entity.ClientInfo{Name: "example-client", Version: "1.0.0"}),
+		client.WithRequestTimeout(10*time.Second),
+		client.WithLogger(slog.New(slog.NewTextHandler(os.Stderr, nil)).With("component", "mcp")),
+		client.WithHandler("notifications/message", func(msg *entity.Message) error {
+			fmt.Printf("server log: %s\n", msg.Params)
+			return nil
+		}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer c.Close()
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		log.Fatalf("Failed to list tools: %v", err)
+	}
+	for _, tool := range tools {
+		fmt.Printf("%s: %s\n", tool.Name, tool.Description)
+	}
+
+	result, err := c.CallTool(ctx, entity.ToolCall{
+		Name:      "echo",
+		Arguments: map[string]interface{}{"message": "hello from an embedded client"},
+	})
+	if err != nil {
+		log.Fatalf("Failed to call tool: %v", err)
+	}
+	for _, content := range result.Content {
+		fmt.Println(content.Text)
+	}
+}
@@ -0,0 +1,181 @@
+// Package client is an embeddable MCP client. It talks to MCP servers without
+// the CLI, configuration files or dependency injection:
+//
+//	c, err := client.Dial(ctx, "ws://localhost:3000",
+//		client.WithClientInfo(entity.ClientInfo{Name: "my-service", Version: "1.0.0"}),
+//		client.WithRequestTimeout(10*time.Second),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	tools, err := c.ListTools(ctx)
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/response"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/infrastructure"
+)
+
+var _ repository.IFMCPRepository = (*Client)(nil)
+
+// Client is a session with one MCP server. It is safe for concurrent use.
+type Client struct {
+	repo       *infrastructure.MCPRepositoryImpl
+	clientInfo entity.ClientInfo
+}
+
+// New creates a client that is not yet connected
+func New(opts ...Option) *Client {
+	o := &options{
+		clientInfo: entity.ClientInfo{Name: "go-mcp-client", Version: "1.0.0"},
+		handlers:   make(map[string]Handler),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	c := &Client{
+		repo:       infrastructure.NewMCPRepositoryImplWithOptions(o.mcp),
+		clientInfo: o.clientInfo,
+	}
+	for method, handler := range o.handlers {
+		c.Handle(method, handler)
+	}
+	if o.defaultHandler != nil {
+		c.SetDefaultHandler(o.defaultHandler)
+	}
+	return c
+}
+
+// Dial connects to a server and completes the initialize handshake
+func Dial(ctx context.Context, serverURL string, opts ...Option) (*Client, error) {
+	c := New(opts...)
+	if err := c.Connect(ctx, serverURL); err != nil {
+		return nil, err
+	}
+	if _, err := c.Initialize(ctx, c.clientInfo); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Connect opens the transport to the server
+func (c *Client) Connect(ctx context.Context, serverURL string) error {
+	return c.repo.Connect(ctx, serverURL)
+}
+
+// ConnectServer connects to a server definition in the mcpServers format,
+// starting local servers as child processes
+func (c *Client) ConnectServer(ctx context.Context, server config.ServerConfig) error {
+	return c.repo.ConnectServer(ctx, server)
+}
+
+// Disconnect closes the connection
+func (c *Client) Disconnect() error {
+	return c.repo.Disconnect()
+}
+
+// Close closes the connection; it is the same as Disconnect
+func (c *Client) Close() error {
+	return c.Disconnect()
+}
+
+// IsConnected returns whether the client is connected
+func (c *Client) IsConnected() bool {
+	return c.repo.IsConnected()
+}
+
+// Handle registers a handler for server messages of one method
+func (c *Client) Handle(method string, handler Handler) {
+	c.repo.RegisterHandler(method, infrastructure.MessageHandler(handler))
+}
+
+// SetDefaultHandler sets the handler for server messages that have no registered handler
+func (c *Client) SetDefaultHandler(handler func(*entity.Message) error) {
+	c.repo.SetDefaultHandler(handler)
+}
+
+// SetTimeouts changes the dial and request timeouts; zero keeps the current value
+func (c *Client) SetTimeouts(dial, request time.Duration) {
+	c.repo.SetTimeouts(dial, request)
+}
+
+// SetCloseHandler sets the handler called when the server closes the connection
+func (c *Client) SetCloseHandler(handler func(err error)) {
+	c.repo.SetCloseHandler(handler)
+}
+
+// SendMessage sends a message, typically a notification, to the server
+func (c *Client) SendMessage(ctx context.Context, message *entity.Message) error {
+	return c.repo.SendMessage(ctx, message)
+}
+
+// ReceiveMessage reads the next message directly from the transport
+func (c *Client) ReceiveMessage(ctx context.Context) (*entity.Message, error) {
+	return c.repo.ReceiveMessage(ctx)
+}
+
+// StartRecording writes every frame sent and received from now on to a JSONL file
+func (c *Client) StartRecording(path string) error {
+	return c.repo.StartRecording(path)
+}
+
+// StopRecording stops recording and closes the recording file
+func (c *Client) StopRecording() error {
+	return c.repo.StopRecording()
+}
+
+// Request sends an arbitrary request and returns the raw result
+func (c *Client) Request(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+	return c.repo.Request(ctx, method, params)
+}
+
+// Initialize performs the initialize handshake
+func (c *Client) Initialize(ctx context.Context, clientInfo entity.ClientInfo) (*response.InitializeResponse, error) {
+	return c.repo.Initialize(ctx, clientInfo)
+}
+
+// Ping checks that the server is responsive
+func (c *Client) Ping(ctx context.Context) error {
+	return c.repo.Ping(ctx)
+}
+
+// ListTools returns every tool offered by the server
+func (c *Client) ListTools(ctx context.Context) ([]entity.Tool, error) {
+	return c.repo.ListTools(ctx)
+}
+
+// CallTool calls a tool
+func (c *Client) CallTool(ctx context.Context, toolCall entity.ToolCall) (*entity.ToolResult, error) {
+	return c.repo.CallTool(ctx, toolCall)
+}
+
+// ListResources returns every resource offered by the server
+func (c *Client) ListResources(ctx context.Context) ([]entity.Resource, error) {
+	return c.repo.ListResources(ctx)
+}
+
+// ReadResource reads the contents of a resource
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]entity.ResourceContents, error) {
+	return c.repo.ReadResource(ctx, uri)
+}
+
+// ListPrompts returns every prompt offered by the server
+func (c *Client) ListPrompts(ctx context.Context) ([]entity.Prompt, error) {
+	return c.repo.ListPrompts(ctx)
+}
+
+// GetPrompt renders a prompt
+func (c *Client) GetPrompt(ctx context.Context, request entity.PromptRequest) (*entity.PromptResult, error) {
+	return c.repo.GetPrompt(ctx, request)
+}
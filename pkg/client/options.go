@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/infrastructure"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Transport carries JSON-RPC frames between the client and a server
+type Transport = infrastructure.Transport
+
+// Dialer opens a transport for a server URL
+type Dialer func(ctx context.Context, serverURL string) (Transport, error)
+
+// Handler handles a message initiated by the server
+type Handler func(*entity.Message) error
+
+// Option configures a Client
+type Option func(*options)
+
+// options collects the settings applied by Option values
+type options struct {
+	mcp            infrastructure.MCPOptions
+	clientInfo     entity.ClientInfo
+	handlers       map[string]Handler
+	defaultHandler Handler
+}
+
+// WithDialer sets how transports are opened. The default dials ws:// and wss://
+// URLs and replays recordings for replay:// URLs.
+func WithDialer(dialer Dialer) Option {
+	return func(o *options) {
+		o.mcp.Dialer = dialer
+	}
+}
+
+// WithTransport makes Connect use an already opened transport and ignore the server URL
+func WithTransport(transport Transport) Option {
+	return WithDialer(func(ctx context.Context, serverURL string) (Transport, error) {
+		return transport, nil
+	})
+}
+
+// WithDialTimeout bounds connection attempts whose context has no deadline
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.mcp.DialTimeout = timeout
+	}
+}
+
+// WithRequestTimeout bounds requests whose context has no deadline
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.mcp.RequestTimeout = timeout
+	}
+}
+
+// WithProtocolVersion sets the protocol version requested during initialization
+func WithProtocolVersion(version string) Option {
+	return func(o *options) {
+		o.mcp.ProtocolVersion = version
+	}
+}
+
+// WithCapabilities sets the client capabilities announced during initialization
+func WithCapabilities(capabilities map[string]interface{}) Option {
+	return func(o *options) {
+		o.mcp.Capabilities = capabilities
+	}
+}
+
+// WithClientInfo sets the client name and version sent by Dial
+func WithClientInfo(info entity.ClientInfo) Option {
+	return func(o *options) {
+		o.clientInfo = info
+	}
+}
+
+// WithHandler handles server messages of one method, such as notifications/message
+func WithHandler(method string, handler Handler) Option {
+	return func(o *options) {
+		o.handlers[method] = handler
+	}
+}
+
+// WithDefaultHandler handles server messages that have no handler of their own
+func WithDefaultHandler(handler Handler) Option {
+	return func(o *options) {
+		o.defaultHandler = handler
+	}
+}
+
+// WithTracerProvider sets the provider of the spans created for connections and
+// requests; the global provider is used otherwise. Tests can pass a provider that
+// records to an in-memory exporter.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.mcp.TracerProvider = provider
+	}
+}
+
+// WithLogger sets the logger for diagnostic messages; slog.Default() is used otherwise
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.mcp.Logger = logger
+	}
+}
@@ -0,0 +1,15 @@
+package client
+
+import (
+	"github.com/google/wire"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
+)
+
+// ProvideClient creates a client with the default options for dependency injection
+func ProvideClient() *Client {
+	return New(WithLogger(logging.Logger("transport")))
+}
+
+var ClientSet = wire.NewSet(
+	ProvideClient,
+)
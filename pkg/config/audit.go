@@ -0,0 +1,15 @@
+package config
+
+// AuditConfig configures the audit log of tool calls
+type AuditConfig struct {
+	// Path is the JSONL file records are appended to; empty disables the audit log
+	Path string `json:"path,omitempty"`
+	// MaxSizeMB rotates the file once it would grow beyond this size; 0 never rotates
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+	// MaxBackups is the number of rotated files kept; 0 keeps all of them
+	MaxBackups int `json:"max_backups,omitempty"`
+	// HashChain links every record to the previous one so that tampering is detectable
+	HashChain bool `json:"hash_chain,omitempty"`
+	// RedactKeys are argument names whose values are redacted in addition to the built-in ones
+	RedactKeys []string `json:"redact_keys,omitempty"`
+}
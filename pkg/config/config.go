@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
 )
 
@@ -9,4 +11,40 @@ type Config struct {
 	ServerURL  string            `json:"server_url"`
 	ClientInfo entity.ClientInfo `json:"client_info"`
 	LogLevel   string            `json:"log_level"`
+	// LogFormat is text or json; LogFrames logs every JSON-RPC frame at debug level
+	LogFormat string `json:"log_format,omitempty"`
+	LogFrames bool   `json:"log_frames,omitempty"`
+	// OTLPEndpoint is the OTLP/HTTP endpoint spans are exported to; empty disables tracing
+	OTLPEndpoint string `json:"otlp_endpoint,omitempty"`
+	// Audit configures the audit log of tool calls
+	Audit AuditConfig `json:"audit,omitzero"`
+	// Policy decides which tool calls are allowed
+	Policy PolicyConfig `json:"policy,omitzero"`
+	// HTTP configures caller authentication of the REST gateway
+	HTTP HTTPConfig `json:"http,omitzero"`
+	// ApprovalTimeout is how long a tool call waits for approval, as a Go duration; empty uses the default
+	ApprovalTimeout string `json:"approval_timeout,omitempty"`
+	// DialTimeout and RequestTimeout are Go durations such as "10s"; empty uses the client defaults
+	DialTimeout    string `json:"dial_timeout,omitempty"`
+	RequestTimeout string `json:"request_timeout,omitempty"`
+	// DefaultServer selects an entry of MCPServers instead of ServerURL
+	DefaultServer string `json:"default_server,omitempty"`
+	// MCPServers defines named servers in the format used by desktop MCP clients
+	MCPServers map[string]ServerConfig `json:"mcpServers,omitempty"`
+	// Profile selects an entry of Profiles that overrides the values above
+	Profile  string             `json:"profile,omitempty"`
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+}
+
+// Timeouts returns the configured dial and request timeouts, zero when unset or invalid
+func (c *Config) Timeouts() (dial, request time.Duration) {
+	dial, _ = time.ParseDuration(c.DialTimeout)
+	request, _ = time.ParseDuration(c.RequestTimeout)
+	return dial, request
+}
+
+// ApprovalWait returns the configured approval timeout, zero when unset or invalid
+func (c *Config) ApprovalWait() time.Duration {
+	timeout, _ := time.ParseDuration(c.ApprovalTimeout)
+	return timeout
 }
@@ -0,0 +1,290 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is the prefix of environment variables that override configuration values
+const EnvPrefix = "MCPCLIENT_"
+
+// durationType is handled as a string such as "30s"
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Keys returns the dotted key of every scalar configuration value, such as client_info.name
+func Keys() []string {
+	var keys []string
+	collectKeys(reflect.TypeOf(Config{}), "", &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+// CompositeKeys returns the dotted key of every configuration value that holds
+// a list or a map, such as mcpServers and policy.rules
+func CompositeKeys() []string {
+	var keys []string
+	collectComposites(reflect.TypeOf(Config{}), "", &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+// collectComposites appends the keys of the slice and map fields of a struct type
+func collectComposites(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			if field.Type != durationType {
+				collectComposites(field.Type, prefix+name+".", keys)
+			}
+		case reflect.Slice, reflect.Map:
+			*keys = append(*keys, prefix+name)
+		}
+	}
+}
+
+// collectKeys appends the keys of the scalar fields of a struct type
+func collectKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		key := prefix + name
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			collectKeys(field.Type, key+".", keys)
+			continue
+		}
+		if isScalar(field.Type) {
+			*keys = append(*keys, key)
+		}
+	}
+}
+
+// EnvVar returns the environment variable that overrides a key,
+// e.g. MCPCLIENT_CLIENT_INFO_NAME for client_info.name
+func EnvVar(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Get returns a configuration value formatted as a string. Lists and maps
+// are formatted as JSON, and as "" when they are empty.
+func (c *Config) Get(key string) (string, error) {
+	v, err := c.lookup(key)
+	if err != nil {
+		return "", err
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return "", nil
+		}
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", key, err)
+		}
+		return string(data), nil
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// Set parses value for the type of the field at key and stores it
+func (c *Config) Set(key, value string) error {
+	v, err := c.field(key)
+	if err != nil {
+		return err
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid duration %q", key, value)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid boolean %q", key, value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid integer %q", key, value)
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", key, value)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("%s: unsupported type %s", key, v.Type())
+	}
+	return nil
+}
+
+// IsZero reports whether the value at key is unset; empty lists and maps are unset
+func (c *Config) IsZero(key string) bool {
+	v, err := c.lookup(key)
+	if err != nil {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// Merge overlays the values of a configuration document, as read from a file,
+// and returns the keys it set. Empty values and unknown keys are ignored, and
+// lists and maps replace the current value as a whole.
+func (c *Config) Merge(document map[string]interface{}) ([]string, error) {
+	scalars, composites := Keys(), CompositeKeys()
+	var keys []string
+	overlay := selectKeys(document, "", func(key string) bool {
+		return slices.Contains(scalars, key) || slices.Contains(composites, key)
+	}, &keys)
+	for _, key := range keys {
+		if slices.Contains(composites, key) {
+			v, _ := c.lookup(key)
+			v.SetZero()
+		}
+	}
+
+	data, err := json.Marshal(overlay)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to merge configuration: %w", err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// selectKeys copies the non-empty values of document at the keys accepted by known,
+// descending into objects, and appends the dotted keys it copied
+func selectKeys(document map[string]interface{}, prefix string, known func(string) bool, keys *[]string) map[string]interface{} {
+	selected := make(map[string]interface{})
+	for name, value := range document {
+		key := prefix + name
+		if known(key) {
+			if !isEmptyValue(value) {
+				selected[name] = value
+				*keys = append(*keys, key)
+			}
+			continue
+		}
+		if object, ok := value.(map[string]interface{}); ok {
+			if nested := selectKeys(object, key+".", known, keys); len(nested) > 0 {
+				selected[name] = nested
+			}
+		}
+	}
+	return selected
+}
+
+// isEmptyValue reports whether a decoded JSON value is null, zero or empty
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// field returns the settable scalar field at a dotted key
+func (c *Config) field(key string) (reflect.Value, error) {
+	v, err := c.lookup(key)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if !isScalar(v.Type()) {
+		return reflect.Value{}, fmt.Errorf("configuration key %q is not a single value", key)
+	}
+	return v, nil
+}
+
+// lookup returns the settable field at a dotted key
+func (c *Config) lookup(key string) (reflect.Value, error) {
+	v := reflect.ValueOf(c).Elem()
+	for _, part := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct || v.Type() == durationType {
+			return reflect.Value{}, fmt.Errorf("unknown configuration key %q", key)
+		}
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			if jsonName(v.Type().Field(i)) == part {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, fmt.Errorf("unknown configuration key %q", key)
+		}
+	}
+	return v, nil
+}
+
+// jsonName returns the JSON name of a struct field, or "" when it is not serialized
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// isScalar reports whether a type holds a single value that Set can parse
+func isScalar(t reflect.Type) bool {
+	if t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
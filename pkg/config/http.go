@@ -0,0 +1,12 @@
+package config
+
+// HTTPConfig configures how the REST gateway of serve identifies callers. A
+// caller it cannot authenticate is identified by its address.
+type HTTPConfig struct {
+	// Users maps user names to the passwords of HTTP basic authentication;
+	// passwords may be secret references
+	Users map[string]string `json:"users,omitempty"`
+	// TrustedProxies are the CIDRs of authenticating proxies whose
+	// X-Forwarded-User and X-Remote-User headers are trusted
+	TrustedProxies []string `json:"trusted_proxies,omitempty"`
+}
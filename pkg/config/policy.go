@@ -0,0 +1,45 @@
+package config
+
+// Policy actions
+const (
+	PolicyAllow = "allow"
+	PolicyDeny  = "deny"
+)
+
+// PolicyConfig decides which tool calls may reach a server. Rules are evaluated
+// in order and the first one matching the server and tool decides; calls no rule
+// matches get Default.
+type PolicyConfig struct {
+	// Default is allow or deny; empty allows
+	Default string       `json:"default,omitempty"`
+	Rules   []PolicyRule `json:"rules,omitempty"`
+}
+
+// PolicyRule matches tool calls by server and tool name
+type PolicyRule struct {
+	// Server and Tool are glob patterns such as "fs*"; empty matches everything.
+	// Server matches the server name in mcpServers or the server address.
+	Server string `json:"server,omitempty"`
+	Tool   string `json:"tool,omitempty"`
+	// Action is allow or deny
+	Action string `json:"action"`
+	// Arguments constrain the arguments of allowed calls by name; a call
+	// violating a constraint is denied
+	Arguments map[string]ArgumentConstraint `json:"arguments,omitempty"`
+	// Confirm requires approval before an allowed call is sent
+	Confirm bool `json:"confirm,omitempty"`
+	// Reason is reported when the rule denies a call
+	Reason string `json:"reason,omitempty"`
+}
+
+// ArgumentConstraint restricts the value of one tool argument. Absent arguments
+// pass unless Required is set.
+type ArgumentConstraint struct {
+	Required bool `json:"required,omitempty"`
+	// Under lists directories an absolute path argument must be in
+	Under []string `json:"under,omitempty"`
+	// Pattern is a regular expression a string argument must match
+	Pattern string `json:"pattern,omitempty"`
+	// Enum lists the values the argument may take
+	Enum []interface{} `json:"enum,omitempty"`
+}
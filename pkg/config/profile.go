@@ -0,0 +1,96 @@
+package config
+
+import "sort"
+
+// Profile overrides part of the configuration for one environment, such as dev,
+// staging or prod. Empty fields keep the base value.
+type Profile struct {
+	ServerURL      string `json:"server_url,omitempty"`
+	LogLevel       string `json:"log_level,omitempty"`
+	DialTimeout    string `json:"dial_timeout,omitempty"`
+	RequestTimeout string `json:"request_timeout,omitempty"`
+	DefaultServer  string `json:"default_server,omitempty"`
+	// MCPServers is merged into the base servers field by field; env and headers are merged by key
+	MCPServers map[string]ServerConfig `json:"mcpServers,omitempty"`
+}
+
+// ProfileNames returns the names of the declared profiles in order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile merges a profile into the configuration and returns the keys it
+// changed; "mcpServers" stands for any change to the servers
+func (c *Config) ApplyProfile(profile Profile) []string {
+	var changed []string
+	override := func(key string, target *string, value string) {
+		if value != "" {
+			*target = value
+			changed = append(changed, key)
+		}
+	}
+	override("server_url", &c.ServerURL, profile.ServerURL)
+	override("log_level", &c.LogLevel, profile.LogLevel)
+	override("dial_timeout", &c.DialTimeout, profile.DialTimeout)
+	override("request_timeout", &c.RequestTimeout, profile.RequestTimeout)
+	override("default_server", &c.DefaultServer, profile.DefaultServer)
+
+	if len(profile.MCPServers) > 0 {
+		servers := make(map[string]ServerConfig, len(c.MCPServers)+len(profile.MCPServers))
+		for name, server := range c.MCPServers {
+			servers[name] = server
+		}
+		for name, server := range profile.MCPServers {
+			servers[name] = mergeServer(servers[name], server)
+		}
+		c.MCPServers = servers
+		changed = append(changed, "mcpServers")
+	}
+	return changed
+}
+
+// mergeServer overrides the fields of base that are set in override.
+// A server cannot be re-enabled by a profile because false is the empty value.
+func mergeServer(base, override ServerConfig) ServerConfig {
+	merged := base
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&merged.Command, override.Command},
+		{&merged.Cwd, override.Cwd},
+		{&merged.URL, override.URL},
+		{&merged.Transport, override.Transport},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	if override.Args != nil {
+		merged.Args = override.Args
+	}
+	merged.Env = mergeMap(base.Env, override.Env)
+	merged.Headers = mergeMap(base.Headers, override.Headers)
+	merged.Disabled = base.Disabled || override.Disabled
+	return merged
+}
+
+// mergeMap returns the entries of base overridden by those of override
+func mergeMap(base, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,77 @@
+package config
+
+// Layer is a configuration source. Later layers take precedence:
+// defaults < file < the selected profile < environment variables < flags.
+type Layer string
+
+const (
+	LayerDefault Layer = "default"
+	LayerFile    Layer = "file"
+	LayerProfile Layer = "profile"
+	LayerEnv     Layer = "env"
+	LayerFlag    Layer = "flag"
+)
+
+// Source records where a configuration value came from
+type Source struct {
+	Layer Layer `json:"layer"`
+	// Name is the file path, environment variable or flag that set the value
+	Name string `json:"name,omitempty"`
+}
+
+// String formats a source as "env (MCPCLIENT_LOG_LEVEL)"
+func (s Source) String() string {
+	if s.Name == "" {
+		return string(s.Layer)
+	}
+	return string(s.Layer) + " (" + s.Name + ")"
+}
+
+// Override is a value given on the command line
+type Override struct {
+	Key   string
+	Value string
+	// Flag is the name of the flag that set the value
+	Flag string
+}
+
+// Resolved is the effective configuration and the source of each value
+type Resolved struct {
+	Config  *Config           `json:"config"`
+	Sources map[string]Source `json:"sources"`
+}
+
+// ActiveServer selects the server to connect to. An explicit server_url from the
+// environment or a flag wins over a default_server taken from a lower layer.
+func (r *Resolved) ActiveServer() (string, ServerConfig, error) {
+	urlSource, defaultSource := r.Sources["server_url"], r.Sources["default_server"]
+	if r.Config.DefaultServer != "" && urlSource.Layer.rank() > defaultSource.Layer.rank() {
+		return "", ServerConfig{URL: r.Config.ServerURL}, nil
+	}
+	return r.Config.ActiveServer()
+}
+
+// rank orders layers by precedence
+func (l Layer) rank() int {
+	switch l {
+	case LayerFlag:
+		return 4
+	case LayerEnv:
+		return 3
+	case LayerProfile:
+		return 2
+	case LayerFile:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Update changes the value at a dotted path such as mcpServers.github.env.TOKEN.
+// A string Value is converted to the type the schema gives the path.
+type Update struct {
+	Path  string
+	Value interface{}
+	// Unset removes the value at Path instead of setting it
+	Unset bool
+}
@@ -0,0 +1,13 @@
+package config
+
+import _ "embed"
+
+// schema is the JSON Schema of the configuration file
+//
+//go:embed schema.json
+var schema []byte
+
+// Schema returns the JSON Schema that describes the configuration file
+func Schema() []byte {
+	return schema
+}
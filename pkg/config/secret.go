@@ -0,0 +1,257 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Kinds of secret references
+const (
+	SecretEnv  = "env"
+	SecretFile = "file"
+	SecretCmd  = "cmd"
+)
+
+// minSecretLength is the shortest resolved value that is redacted; shorter
+// values would blank out unrelated text in logs
+const minSecretLength = 4
+
+// redactedValue replaces secret values in output
+const redactedValue = "[REDACTED]"
+
+// secretPattern matches references such as ${env:GITHUB_TOKEN} inside a value
+var secretPattern = regexp.MustCompile(`\$\{(\w+):([^}]*)\}`)
+
+// SecretResolver returns the value of one reference, such as the variable name of ${env:NAME}
+type SecretResolver func(kind, reference string) (string, error)
+
+// HasSecretRef reports whether a value contains a secret reference
+func HasSecretRef(value string) bool {
+	return secretPattern.MatchString(value)
+}
+
+// ExpandSecrets returns a copy of the configuration with the secret references in
+// server_url, the passwords of http.users and the server definitions replaced by
+// their values, and the values that were resolved. The receiver keeps the
+// references, so it is the one to save.
+func (c *Config) ExpandSecrets(resolve SecretResolver) (*Config, []string, error) {
+	var secrets []string
+	expanded, err := c.mapStrings(func(path, value string) (string, error) {
+		var err error
+		result := secretPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			if err != nil {
+				return ""
+			}
+			match := secretPattern.FindStringSubmatch(ref)
+			kind, reference := match[1], strings.TrimSpace(match[2])
+			switch kind {
+			case SecretEnv, SecretFile, SecretCmd:
+			default:
+				err = fmt.Errorf("%s: unknown secret reference ${%s:...} (use env, file or cmd)", path, kind)
+				return ""
+			}
+			secret, resolveErr := resolve(kind, reference)
+			if resolveErr != nil {
+				err = fmt.Errorf("%s: failed to resolve ${%s:%s}: %w", path, kind, reference, resolveErr)
+				return ""
+			}
+			secrets = append(secrets, secret)
+			return secret
+		})
+		return result, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return expanded, secrets, nil
+}
+
+// mapStrings returns a deep copy of the configuration with server_url, the
+// passwords of http.users and every string of the server definitions passed through fn
+func (c *Config) mapStrings(fn func(path, value string) (string, error)) (*Config, error) {
+	mapped := *c
+	var err error
+	if mapped.ServerURL, err = fn("server_url", c.ServerURL); err != nil {
+		return nil, err
+	}
+
+	if c.HTTP.Users != nil {
+		names := make([]string, 0, len(c.HTTP.Users))
+		for name := range c.HTTP.Users {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		mapped.HTTP.Users = make(map[string]string, len(c.HTTP.Users))
+		for _, name := range names {
+			if mapped.HTTP.Users[name], err = fn("http.users."+name, c.HTTP.Users[name]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if c.MCPServers == nil {
+		return &mapped, nil
+	}
+	mapped.MCPServers = make(map[string]ServerConfig, len(c.MCPServers))
+	for _, name := range c.ServerNames() {
+		server, err := c.MCPServers[name].mapStrings("mcpServers."+name, fn)
+		if err != nil {
+			return nil, err
+		}
+		mapped.MCPServers[name] = server
+	}
+	return &mapped, nil
+}
+
+// mapStrings returns a copy of the server definition with every string passed through fn
+func (s ServerConfig) mapStrings(path string, fn func(path, value string) (string, error)) (ServerConfig, error) {
+	var err error
+	field := func(name, value string) string {
+		if err != nil {
+			return ""
+		}
+		var result string
+		result, err = fn(path+"."+name, value)
+		return result
+	}
+
+	mapped := s
+	mapped.Command = field("command", s.Command)
+	mapped.Cwd = field("cwd", s.Cwd)
+	mapped.URL = field("url", s.URL)
+	if s.Args != nil {
+		mapped.Args = make([]string, len(s.Args))
+		for i, arg := range s.Args {
+			mapped.Args[i] = field(fmt.Sprintf("args[%d]", i), arg)
+		}
+	}
+	mapped.Env = mapValues(s.Env, func(key, value string) string { return field("env."+key, value) })
+	mapped.Headers = mapValues(s.Headers, func(key, value string) string { return field("headers."+key, value) })
+	return mapped, err
+}
+
+// mapValues copies a map with every value passed through fn, in key order
+func mapValues(m map[string]string, fn func(key, value string) string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	mapped := make(map[string]string, len(m))
+	for _, key := range keys {
+		mapped[key] = fn(key, m[key])
+	}
+	return mapped
+}
+
+// Redactor replaces known secret values in text. It is safe for concurrent use.
+type Redactor struct {
+	mu       sync.RWMutex
+	secrets  map[string]struct{}
+	replacer *strings.Replacer
+}
+
+// DefaultRedactor holds the secrets resolved by this process; the log output is filtered through it
+var DefaultRedactor = NewRedactor()
+
+// NewRedactor creates an empty redactor
+func NewRedactor() *Redactor {
+	return &Redactor{secrets: make(map[string]struct{})}
+}
+
+// Add registers secret values. Values shorter than four characters are ignored.
+func (r *Redactor) Add(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := false
+	for _, value := range values {
+		if len(value) < minSecretLength {
+			continue
+		}
+		if _, ok := r.secrets[value]; !ok {
+			r.secrets[value] = struct{}{}
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	// Longer secrets first so a secret containing another is replaced whole
+	secrets := make([]string, 0, len(r.secrets))
+	for secret := range r.secrets {
+		secrets = append(secrets, secret)
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+
+	pairs := make([]string, 0, 2*len(secrets))
+	for _, secret := range secrets {
+		pairs = append(pairs, secret, redactedValue)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// Redact replaces every registered secret in s
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.replacer == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
+
+// Contains reports whether s contains a registered secret
+func (r *Redactor) Contains(s string) bool {
+	return r.Redact(s) != s
+}
+
+// RedactConfig returns a copy of the configuration with every registered secret replaced
+func (r *Redactor) RedactConfig(c *Config) *Config {
+	// The callback never fails, so neither does mapStrings
+	redacted, _ := c.mapStrings(func(path, value string) (string, error) {
+		return r.Redact(value), nil
+	})
+	return redacted
+}
+
+// ContainsConfig reports whether a configuration holds a registered secret,
+// which means its references were expanded
+func (r *Redactor) ContainsConfig(c *Config) bool {
+	found := false
+	// The callback never fails, so neither does mapStrings
+	_, _ = c.mapStrings(func(path, value string) (string, error) {
+		found = found || r.Contains(value)
+		return value, nil
+	})
+	return found
+}
+
+// Writer returns a writer that redacts registered secrets before writing to w.
+// Each Write must carry whole lines, as the log package does.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &redactingWriter{redactor: r, w: w}
+}
+
+// redactingWriter is the writer returned by Redactor.Writer
+type redactingWriter struct {
+	redactor *Redactor
+	w        io.Writer
+}
+
+// Write implements io.Writer
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, w.redactor.Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
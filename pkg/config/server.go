@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Transports of a server definition
+const (
+	TransportStdio     = "stdio"
+	TransportWebSocket = "websocket"
+	TransportSSE       = "sse"
+	TransportHTTP      = "http"
+)
+
+// ServerConfig defines one MCP server in the mcpServers format used by desktop MCP clients.
+// Local servers set Command; remote servers set URL.
+type ServerConfig struct {
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Cwd       string            `json:"cwd,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Transport string            `json:"transport,omitempty"`
+	Disabled  bool              `json:"disabled,omitempty"`
+}
+
+// TransportType returns the transport of the server, inferred from Command or
+// the URL scheme when Transport is not set
+func (s ServerConfig) TransportType() string {
+	if s.Transport != "" {
+		return strings.ToLower(s.Transport)
+	}
+	switch {
+	case s.Command != "":
+		return TransportStdio
+	case strings.HasPrefix(s.URL, "http://"), strings.HasPrefix(s.URL, "https://"):
+		return TransportHTTP
+	default:
+		return TransportWebSocket
+	}
+}
+
+// Address describes the server for logs and status output
+func (s ServerConfig) Address() string {
+	if s.TransportType() == TransportStdio {
+		return strings.TrimSpace("stdio:" + s.Command + " " + strings.Join(s.Args, " "))
+	}
+	return s.URL
+}
+
+// ServerNames returns the names of the configured servers in order
+func (c *Config) ServerNames() []string {
+	names := make([]string, 0, len(c.MCPServers))
+	for name := range c.MCPServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveServer returns the server to connect to: the mcpServers entry named by
+// default_server when it is set, otherwise server_url
+func (c *Config) ActiveServer() (string, ServerConfig, error) {
+	if c.DefaultServer == "" {
+		return "", ServerConfig{URL: c.ServerURL}, nil
+	}
+
+	server, ok := c.MCPServers[c.DefaultServer]
+	if !ok {
+		return "", ServerConfig{}, fmt.Errorf("no server named %q in mcpServers (have: %s)", c.DefaultServer, strings.Join(c.ServerNames(), ", "))
+	}
+	if server.Disabled {
+		return "", ServerConfig{}, fmt.Errorf("server %q is disabled", c.DefaultServer)
+	}
+	return c.DefaultServer, server, nil
+}
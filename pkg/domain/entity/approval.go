@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// ApprovalRequest is a tool call waiting for a human to approve it
+type ApprovalRequest struct {
+	ID      string `json:"id"`
+	Session string `json:"session"`
+	Server  string `json:"server"`
+	Tool    string `json:"tool"`
+	// Arguments are the arguments the tool will be called with once approved
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	// Reason says why the call needs approval
+	Reason string `json:"reason"`
+	// Caller identifies who made the call
+	Caller    string    `json:"caller,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Approval is the outcome of an approval request
+type Approval struct {
+	Decision ApprovalDecision `json:"decision"`
+	// Approver identifies who decided, empty when the request timed out
+	Approver string `json:"approver,omitempty"`
+}
+
+// ApprovalDecision is how an approval request ended
+type ApprovalDecision string
+
+const (
+	ApprovalApproved ApprovalDecision = "approved"
+	ApprovalDenied   ApprovalDecision = "denied"
+	ApprovalTimedOut ApprovalDecision = "timeout"
+)
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// AuditRecord is one entry of the audit log of tool calls
+type AuditRecord struct {
+	Time    time.Time `json:"time"`
+	Session string    `json:"session"`
+	Server  string    `json:"server"`
+	Tool    string    `json:"tool"`
+	// Arguments are the tool arguments with sensitive values redacted
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Status    AuditStatus            `json:"status"`
+	Error     string                 `json:"error,omitempty"`
+	// DurationMS is how long the call took in milliseconds
+	DurationMS float64 `json:"duration_ms"`
+	// Caller identifies who made the call, such as cli:alice or http:bob
+	Caller string `json:"caller,omitempty"`
+	// Approval is how the call was approved or rejected, when it needed approval
+	Approval *Approval `json:"approval,omitempty"`
+	// PrevHash and Hash chain the records when the hash chain is enabled
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// AuditStatus is the outcome of an audited tool call
+type AuditStatus string
+
+const (
+	// AuditStatusSuccess means the tool returned a result
+	AuditStatusSuccess AuditStatus = "success"
+	// AuditStatusToolError means the tool returned a result with isError set
+	AuditStatusToolError AuditStatus = "tool_error"
+	// AuditStatusError means the call failed before a result was returned
+	AuditStatusError AuditStatus = "error"
+	// AuditStatusDenied means the policy kept the call from reaching the server
+	AuditStatusDenied AuditStatus = "denied"
+)
@@ -6,11 +6,13 @@ import (
 
 // Connection represents a connection state
 type Connection struct {
-	ID        string
-	ServerURL string
-	Status    ConnectionStatus
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID              string           `json:"id"`
+	ServerURL       string           `json:"serverUrl"`
+	Status          ConnectionStatus `json:"status"`
+	ProtocolVersion string           `json:"protocolVersion,omitempty"`
+	ServerInfo      *ServerInfo      `json:"serverInfo,omitempty"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
 }
 
 // ConnectionStatus represents the status of a connection
@@ -2,15 +2,20 @@ package entity
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
+// JSONRPCVersion is the JSON-RPC version used by MCP messages
+const JSONRPCVersion = "2.0"
+
 // Message represents a generic MCP message
 type Message struct {
-	ID     string          `json:"id"`
-	Method string          `json:"method"`
-	Params json.RawMessage `json:"params,omitempty"`
-	Result json.RawMessage `json:"result,omitempty"`
-	Error  *Error          `json:"error,omitempty"`
+	JSONRPC string          `json:"jsonrpc,omitempty"`
+	ID      string          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
 }
 
 // Error represents an MCP error
@@ -19,3 +24,8 @@ type Error struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return fmt.Sprintf("MCP error %d: %s", e.Code, e.Message)
+}
@@ -5,6 +5,28 @@ type Tool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
+	// OutputSchema describes StructuredContent of the result, when the tool declares it
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations       `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are hints the server gives about the behavior of a tool. They
+// are not guaranteed to be accurate.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// Destructive reports whether the server explicitly marks a tool that may modify
+// its environment as destructive
+func (a *ToolAnnotations) Destructive() bool {
+	if a == nil || a.DestructiveHint == nil || !*a.DestructiveHint {
+		return false
+	}
+	return a.ReadOnlyHint == nil || !*a.ReadOnlyHint
 }
 
 // ToolCall represents a tool call request
@@ -15,14 +37,17 @@ type ToolCall struct {
 
 // ToolResult represents a tool call result
 type ToolResult struct {
-	Content []Content `json:"content"`
-	IsError bool      `json:"isError"`
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError"`
 }
 
 // Content represents content in a tool result
 type Content struct {
-	Type     string      `json:"type"`
-	Text     string      `json:"text,omitempty"`
-	ImageURL string      `json:"imageUrl,omitempty"`
-	Data     interface{} `json:"data,omitempty"`
+	Type     string            `json:"type"`
+	Text     string            `json:"text,omitempty"`
+	ImageURL string            `json:"imageUrl,omitempty"`
+	Data     interface{}       `json:"data,omitempty"`
+	MimeType string            `json:"mimeType,omitempty"`
+	Resource *ResourceContents `json:"resource,omitempty"`
 }
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// IFAuditRepository stores the append-only audit log of tool calls
+type IFAuditRepository interface {
+	// Open starts appending to the file of cfg, closing the previous one
+	Open(cfg config.AuditConfig) error
+	// Append writes a record; it does nothing while no audit log is configured and
+	// fails when the configured log cannot be written
+	Append(ctx context.Context, record *entity.AuditRecord) error
+	Close() error
+	// Files returns the rotated files of an audit log followed by the log itself, oldest first
+	Files(path string) ([]string, error)
+	// Verify checks the hash chain of a file starting from prevHash, which is empty
+	// to trust the first record, and returns the last hash and the number of records
+	Verify(ctx context.Context, path, prevHash string) (lastHash string, records int, err error)
+}
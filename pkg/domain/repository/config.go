@@ -8,6 +8,9 @@ import (
 
 // IFConfigRepository defines the interface for configuration operations
 type IFConfigRepository interface {
-	Load(ctx context.Context) (*config.Config, error)
-	Save(ctx context.Context, config *config.Config) error
+	Load(ctx context.Context, path string) (*config.Config, error)
+	LoadDocument(ctx context.Context, path string) (map[string]interface{}, error)
+	Save(ctx context.Context, config *config.Config, path string) error
+	ImportServers(ctx context.Context, path string) (map[string]config.ServerConfig, error)
+	Watch(ctx context.Context, path string, onChange func()) error
 }
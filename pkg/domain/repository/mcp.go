@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/response"
 )
@@ -11,15 +14,29 @@ import (
 type IFMCPRepository interface {
 	// Connection management
 	Connect(ctx context.Context, serverURL string) error
+	ConnectServer(ctx context.Context, server config.ServerConfig) error
 	Disconnect() error
 	IsConnected() bool
+	SetTimeouts(dial, request time.Duration)
 
 	// Message handling
 	SendMessage(ctx context.Context, message *entity.Message) error
 	ReceiveMessage(ctx context.Context) (*entity.Message, error)
+	SetDefaultHandler(handler func(*entity.Message) error)
+	SetCloseHandler(handler func(err error))
+
+	// Traffic recording
+	StartRecording(path string) error
+	StopRecording() error
 
 	// Protocol operations
+	Request(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error)
 	Initialize(ctx context.Context, clientInfo entity.ClientInfo) (*response.InitializeResponse, error)
+	Ping(ctx context.Context) error
 	ListTools(ctx context.Context) ([]entity.Tool, error)
 	CallTool(ctx context.Context, toolCall entity.ToolCall) (*entity.ToolResult, error)
+	ListResources(ctx context.Context) ([]entity.Resource, error)
+	ReadResource(ctx context.Context, uri string) ([]entity.ResourceContents, error)
+	ListPrompts(ctx context.Context) ([]entity.Prompt, error)
+	GetPrompt(ctx context.Context, request entity.PromptRequest) (*entity.PromptResult, error)
 }
@@ -0,0 +1,8 @@
+package repository
+
+import "context"
+
+// IFSecretRepository resolves secret references such as ${env:NAME} in the configuration
+type IFSecretRepository interface {
+	Resolve(ctx context.Context, kind, reference string) (string, error)
+}
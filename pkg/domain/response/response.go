@@ -1,6 +1,8 @@
 package response
 
 import (
+	"encoding/json"
+
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
 )
 
@@ -9,9 +11,52 @@ type InitializeResponse struct {
 	ProtocolVersion string             `json:"protocolVersion"`
 	Capabilities    ServerCapabilities `json:"capabilities"`
 	ServerInfo      entity.ServerInfo  `json:"serverInfo"`
+	Instructions    string             `json:"instructions,omitempty"`
 }
 
 // ServerCapabilities represents server capabilities
 type ServerCapabilities struct {
-	Tools map[string]interface{} `json:"tools,omitempty"`
+	Tools     map[string]interface{} `json:"tools,omitempty"`
+	Resources map[string]interface{} `json:"resources,omitempty"`
+	Prompts   map[string]interface{} `json:"prompts,omitempty"`
+	Logging   map[string]interface{} `json:"logging,omitempty"`
+}
+
+// MarshalJSON keeps capabilities the server declared with an empty object
+func (c ServerCapabilities) MarshalJSON() ([]byte, error) {
+	declared := make(map[string]map[string]interface{})
+	for name, value := range map[string]map[string]interface{}{
+		"tools":     c.Tools,
+		"resources": c.Resources,
+		"prompts":   c.Prompts,
+		"logging":   c.Logging,
+	} {
+		if value != nil {
+			declared[name] = value
+		}
+	}
+	return json.Marshal(declared)
+}
+
+// ListToolsResponse represents the tools/list response
+type ListToolsResponse struct {
+	Tools      []entity.Tool `json:"tools"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// ListResourcesResponse represents the resources/list response
+type ListResourcesResponse struct {
+	Resources  []entity.Resource `json:"resources"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+// ReadResourceResponse represents the resources/read response
+type ReadResourceResponse struct {
+	Contents []entity.ResourceContents `json:"contents"`
+}
+
+// ListPromptsResponse represents the prompts/list response
+type ListPromptsResponse struct {
+	Prompts    []entity.Prompt `json:"prompts"`
+	NextCursor string          `json:"nextCursor,omitempty"`
 }
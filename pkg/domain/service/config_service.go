@@ -12,4 +12,5 @@ type ConfigService interface {
 	SaveConfiguration(ctx context.Context, config *config.Config, filepath string) error
 	GetDefaultConfiguration(ctx context.Context) *config.Config
 	ValidateConfiguration(ctx context.Context, config *config.Config) error
+	ResolveConfiguration(ctx context.Context, filepath string, overrides []config.Override) (*config.Resolved, error)
 }
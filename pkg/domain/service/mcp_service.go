@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/response"
 )
@@ -11,13 +13,22 @@ import (
 type MCPService interface {
 	// Connection management
 	EstablishConnection(ctx context.Context, serverURL string) error
+	EstablishServerConnection(ctx context.Context, name string, server config.ServerConfig) error
 	CloseConnection(ctx context.Context) error
 	GetConnectionStatus(ctx context.Context) entity.ConnectionStatus
+	GetConnection(ctx context.Context) entity.Connection
 
 	// Protocol operations
 	InitializeProtocol(ctx context.Context, clientInfo entity.ClientInfo) (*response.InitializeResponse, error)
 	GetAvailableTools(ctx context.Context) ([]entity.Tool, error)
+	GetTool(ctx context.Context, name string) (*entity.Tool, error)
 	ExecuteTool(ctx context.Context, toolCall entity.ToolCall) (*entity.ToolResult, error)
+	Ping(ctx context.Context) error
+	SendRequest(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error)
+	GetAvailableResources(ctx context.Context) ([]entity.Resource, error)
+	ReadResource(ctx context.Context, uri string) ([]entity.ResourceContents, error)
+	GetAvailablePrompts(ctx context.Context) ([]entity.Prompt, error)
+	GetPrompt(ctx context.Context, request entity.PromptRequest) (*entity.PromptResult, error)
 
 	// Message handling
 	HandleIncomingMessage(ctx context.Context, message *entity.Message) error
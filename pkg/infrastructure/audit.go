@@ -0,0 +1,391 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
+)
+
+var _ repository.IFAuditRepository = (*AuditRepositoryImpl)(nil)
+
+// auditRotationLayout is appended to the path of a rotated audit log; it sorts chronologically
+const auditRotationLayout = "20060102T150405.000000000Z"
+
+// ErrAuditClosed is returned by Append when the audit log was opened but has no file to write to
+var ErrAuditClosed = errors.New("audit log is closed")
+
+// auditHashField separates the hash from the hashed part of a chained record
+var auditHashField = []byte(`,"hash":"`)
+
+// AuditRepositoryImpl appends audit records to a JSONL file. Rotated files are
+// renamed to path.TIMESTAMP. With the hash chain enabled every record carries the
+// hash of the previous one and a SHA-256 hash of itself, continuing across rotations.
+// Processes sharing a log take turns through the lock file path.lock.
+type AuditRepositoryImpl struct {
+	mu  sync.Mutex
+	cfg config.AuditConfig
+	// enabled is set between Open with a path and Close; records are only dropped while it is unset
+	enabled bool
+	file    *os.File
+	lock    *os.File
+	// size and lastHash describe the log as last read while holding the lock
+	size     int64
+	lastHash string
+}
+
+// NewAuditRepositoryImpl creates an audit repository with no file open
+func NewAuditRepositoryImpl() *AuditRepositoryImpl {
+	return &AuditRepositoryImpl{}
+}
+
+// Open starts appending to the file of cfg, closing the previous one. The hash
+// chain continues from the last record of the file, or of its newest rotation.
+// When the new file cannot be opened the previous one stays in use.
+func (r *AuditRepositoryImpl) Open(cfg config.AuditConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cfg.Path == "" {
+		r.enabled = false
+		return r.closeLocked()
+	}
+
+	file, err := os.OpenFile(cfg.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	lock, err := os.OpenFile(cfg.Path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open audit log lock: %w", err)
+	}
+	// An unreadable chain is reported now rather than on the first record
+	if cfg.HashChain {
+		if _, err := r.lastChainHash(cfg.Path); err != nil {
+			file.Close()
+			lock.Close()
+			return err
+		}
+	}
+
+	closeErr := r.closeLocked()
+	r.cfg = cfg
+	r.enabled = true
+	r.file = file
+	r.lock = lock
+	return closeErr
+}
+
+// Append writes a record as one line and syncs it to disk. With the hash chain
+// enabled PrevHash and Hash of the record are filled in. Records are dropped
+// only while no audit log is configured.
+func (r *AuditRepositoryImpl) Append(ctx context.Context, record *entity.AuditRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.enabled {
+		return nil
+	}
+	if r.file == nil {
+		return ErrAuditClosed
+	}
+
+	// Another process may have appended to or rotated the log since our last record
+	if err := lockFile(r.lock); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	err := r.appendLocked(record)
+	if unlockErr := unlockFile(r.lock); unlockErr != nil && err == nil {
+		err = fmt.Errorf("failed to unlock audit log: %w", unlockErr)
+	}
+	return err
+}
+
+// appendLocked writes a record to the log. The caller must hold r.mu and the file lock.
+func (r *AuditRepositoryImpl) appendLocked(record *entity.AuditRecord) error {
+	if err := r.refreshLocked(); err != nil {
+		return err
+	}
+
+	record.PrevHash, record.Hash = "", ""
+	if r.cfg.HashChain {
+		record.PrevHash = r.lastHash
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	if r.cfg.HashChain {
+		record.Hash = auditHash(line)
+		line = append(line[:len(line)-1], auditHashField...)
+		line = append(line, record.Hash+`"}`...)
+	}
+	line = append(line, '\n')
+
+	// A failed rotation is reported once the record is written to the open file
+	rotateErr := r.rotateIfNeeded(int64(len(line)))
+	if _, err := r.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	if err := r.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+	return rotateErr
+}
+
+// Close closes the audit log; records appended afterwards are dropped
+func (r *AuditRepositoryImpl) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enabled = false
+	return r.closeLocked()
+}
+
+// Files returns the rotated files of an audit log followed by the log itself, oldest first
+func (r *AuditRepositoryImpl) Files(path string) ([]string, error) {
+	files, err := rotatedAuditFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// Verify checks that every record of a file is chained to the previous one and
+// that its hash matches its contents
+func (r *AuditRepositoryImpl) Verify(ctx context.Context, path, prevHash string) (string, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	records := 0
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			hash, prev, verifyErr := verifyAuditLine(bytes.TrimRight(line, "\r\n"))
+			if verifyErr != nil {
+				return "", records, fmt.Errorf("%s:%d: %w", path, lineNumber, verifyErr)
+			}
+			if (records > 0 || prevHash != "") && prev != prevHash {
+				return "", records, fmt.Errorf("%s:%d: record is not chained to the previous one", path, lineNumber)
+			}
+			prevHash = hash
+			records++
+		}
+		if err == io.EOF {
+			return prevHash, records, nil
+		}
+		if err != nil {
+			return "", records, fmt.Errorf("failed to read audit log: %w", err)
+		}
+	}
+}
+
+// closeLocked closes the open file. The caller must hold r.mu.
+func (r *AuditRepositoryImpl) closeLocked() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	lockErr := r.lock.Close()
+	r.file, r.lock = nil, nil
+	if err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	if lockErr != nil {
+		return fmt.Errorf("failed to close audit log lock: %w", lockErr)
+	}
+	return nil
+}
+
+// refreshLocked reopens the log when another process rotated it and reads its
+// size and last hash. The caller must hold r.mu and the file lock.
+func (r *AuditRepositoryImpl) refreshLocked() error {
+	info, err := r.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if current, err := os.Stat(r.cfg.Path); err != nil || !os.SameFile(info, current) {
+		file, err := os.OpenFile(r.cfg.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open audit log: %w", err)
+		}
+		if info, err = file.Stat(); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+		old := r.file
+		r.file = file
+		if err := old.Close(); err != nil {
+			return fmt.Errorf("failed to close rotated audit log: %w", err)
+		}
+	}
+	r.size = info.Size()
+
+	if r.cfg.HashChain {
+		if r.lastHash, err = r.lastChainHash(r.cfg.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotateIfNeeded starts a new file when writing n bytes would exceed the maximum
+// size and removes the oldest rotations beyond MaxBackups. r.file stays open
+// whether it succeeds or not. The caller must hold r.mu.
+func (r *AuditRepositoryImpl) rotateIfNeeded(n int64) error {
+	maxSize := int64(r.cfg.MaxSizeMB) << 20
+	if maxSize <= 0 || r.size == 0 || r.size+n <= maxSize {
+		return nil
+	}
+
+	// The open file keeps receiving records until its replacement is open, so a
+	// failed rotation leaves the log writable
+	rotated := r.cfg.Path + "." + time.Now().UTC().Format(auditRotationLayout)
+	if err := os.Rename(r.cfg.Path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	file, err := os.OpenFile(r.cfg.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if renameErr := os.Rename(rotated, r.cfg.Path); renameErr != nil {
+			return fmt.Errorf("failed to open audit log: %w; records continue in %s", err, rotated)
+		}
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	old := r.file
+	r.file = file
+	r.size = 0
+	if err := old.Close(); err != nil {
+		return fmt.Errorf("failed to close rotated audit log: %w", err)
+	}
+
+	if r.cfg.MaxBackups > 0 {
+		backups, err := rotatedAuditFiles(r.cfg.Path)
+		if err != nil {
+			return err
+		}
+		for len(backups) > r.cfg.MaxBackups {
+			if err := os.Remove(backups[0]); err != nil {
+				return fmt.Errorf("failed to remove old audit log: %w", err)
+			}
+			backups = backups[1:]
+		}
+	}
+	return nil
+}
+
+// lastChainHash returns the hash of the last record of the audit log, looking at
+// the newest rotation when the log itself is empty
+func (r *AuditRepositoryImpl) lastChainHash(path string) (string, error) {
+	files, err := r.Files(path)
+	if err != nil {
+		return "", err
+	}
+	for i := len(files) - 1; i >= 0; i-- {
+		line, err := lastLine(files[i])
+		if err != nil {
+			return "", err
+		}
+		if line == nil {
+			continue
+		}
+		var record entity.AuditRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return "", fmt.Errorf("failed to read the last record of %s: %w", files[i], err)
+		}
+		return record.Hash, nil
+	}
+	return "", nil
+}
+
+// rotatedAuditFiles returns the rotations of an audit log, oldest first
+func rotatedAuditFiles(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	var files []string
+	for _, match := range matches {
+		suffix := strings.TrimPrefix(match, path+".")
+		if _, err := time.Parse(auditRotationLayout, suffix); err == nil {
+			files = append(files, match)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// lastLine returns the last non-empty line of a file, or nil when it has none.
+// The file is read backwards from its end, since it is read before every record.
+func lastLine(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var tail []byte
+	for end := info.Size(); end > 0; {
+		chunk := make([]byte, min(end, 4096))
+		end -= int64(len(chunk))
+		if _, err := file.ReadAt(chunk, end); err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+		tail = bytes.TrimRight(append(chunk, tail...), "\r\n")
+		if i := bytes.LastIndexByte(tail, '\n'); i >= 0 {
+			return tail[i+1:], nil
+		}
+	}
+	if len(tail) == 0 {
+		return nil, nil
+	}
+	return tail, nil
+}
+
+// verifyAuditLine checks the hash of a chained record and returns it with the previous hash
+func verifyAuditLine(line []byte) (hash, prevHash string, err error) {
+	var record entity.AuditRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return "", "", fmt.Errorf("invalid record: %w", err)
+	}
+	i := bytes.LastIndex(line, auditHashField)
+	if record.Hash == "" || i < 0 {
+		return "", "", fmt.Errorf("record has no hash")
+	}
+
+	hashed := append(append([]byte{}, line[:i]...), '}')
+	if auditHash(hashed) != record.Hash {
+		return "", "", fmt.Errorf("hash mismatch, the record was modified")
+	}
+	return record.Hash, record.PrevHash, nil
+}
+
+// auditHash returns the hex encoded SHA-256 hash of a record without its hash
+func auditHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
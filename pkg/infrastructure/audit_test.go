@@ -0,0 +1,233 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// verifyAuditChain verifies every file of an audit log in order and returns the number of records
+func verifyAuditChain(t *testing.T, repo *AuditRepositoryImpl, path string) (int, error) {
+	t.Helper()
+	files, err := repo.Files(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total, prevHash := 0, ""
+	for _, file := range files {
+		lastHash, records, err := repo.Verify(context.Background(), file, prevHash)
+		total += records
+		if err != nil {
+			return total, err
+		}
+		if records > 0 {
+			prevHash = lastHash
+		}
+	}
+	return total, nil
+}
+
+func TestAuditHashChainAcrossRotations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	repo := NewAuditRepositoryImpl()
+	if err := repo.Open(config.AuditConfig{Path: path, MaxSizeMB: 1, HashChain: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each record is a little over 300 KiB, so a file holds three of them
+	large := strings.Repeat("x", 300<<10)
+	const records = 10
+	for i := 0; i < records; i++ {
+		record := &entity.AuditRecord{Tool: "write_file", Arguments: map[string]interface{}{"content": large}, Status: entity.AuditStatusSuccess}
+		if err := repo.Append(context.Background(), record); err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+	}
+
+	// Reopening continues the chain from the last record
+	if err := repo.Open(config.AuditConfig{Path: path, MaxSizeMB: 1, HashChain: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Append(context.Background(), &entity.AuditRecord{Tool: "echo", Status: entity.AuditStatusSuccess}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := repo.Files(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 4 {
+		t.Fatalf("got %d files, want the log to have rotated at least three times", len(files))
+	}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 1<<20 {
+			t.Errorf("%s has %d bytes, more than max_size_mb", file, info.Size())
+		}
+	}
+
+	total, err := verifyAuditChain(t, repo, path)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if total != records+1 {
+		t.Errorf("verified %d records, want %d", total, records+1)
+	}
+}
+
+func TestAuditHashChainWithTwoWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := config.AuditConfig{Path: path, MaxSizeMB: 1, HashChain: true}
+
+	// Each repository has its own files, as a second process would
+	writers := []*AuditRepositoryImpl{NewAuditRepositoryImpl(), NewAuditRepositoryImpl()}
+	for _, repo := range writers {
+		if err := repo.Open(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Records of about 200 KiB rotate the log while both write
+	large := strings.Repeat("x", 200<<10)
+	const records = 12
+	var wg sync.WaitGroup
+	errs := make(chan error, len(writers)*records)
+	for _, repo := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < records; i++ {
+				record := &entity.AuditRecord{Tool: "write_file", Arguments: map[string]interface{}{"content": large}, Status: entity.AuditStatusSuccess}
+				if err := repo.Append(context.Background(), record); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	for _, repo := range writers {
+		if err := repo.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	total, err := verifyAuditChain(t, writers[0], path)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if total != len(writers)*records {
+		t.Errorf("verified %d records, want %d", total, len(writers)*records)
+	}
+}
+
+func TestAuditVerifyDetectsRemovedRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	repo := NewAuditRepositoryImpl()
+	if err := repo.Open(config.AuditConfig{Path: path, MaxSizeMB: 1, HashChain: true}); err != nil {
+		t.Fatal(err)
+	}
+	large := strings.Repeat("x", 600<<10)
+	for i := 0; i < 3; i++ {
+		if err := repo.Append(context.Background(), &entity.AuditRecord{Tool: "write_file", Arguments: map[string]interface{}{"content": large}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := repo.Files(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("got %d files, want 3", len(files))
+	}
+	if err := os.Remove(files[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyAuditChain(t, repo, path); err == nil || !strings.Contains(err.Error(), "not chained") {
+		t.Errorf("verify = %v, want a broken chain", err)
+	}
+}
+
+func TestAuditVerifyDetectsModifiedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	repo := NewAuditRepositoryImpl()
+	if err := repo.Open(config.AuditConfig{Path: path, HashChain: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range []string{"read_file", "delete_file"} {
+		if err := repo.Append(context.Background(), &entity.AuditRecord{Tool: tool, Status: entity.AuditStatusSuccess}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), "delete_file", "list_files", 1)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyAuditChain(t, repo, path); err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Errorf("verify = %v, want a hash mismatch", err)
+	}
+}
+
+func TestAuditAppend(t *testing.T) {
+	repo := NewAuditRepositoryImpl()
+	if err := repo.Append(context.Background(), &entity.AuditRecord{Tool: "echo"}); err != nil {
+		t.Errorf("append without an audit log = %v, want the record dropped", err)
+	}
+
+	if err := repo.Open(config.AuditConfig{Path: filepath.Join(t.TempDir(), "missing", "audit.jsonl")}); err == nil {
+		t.Fatal("open in a missing directory succeeded")
+	}
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := repo.Open(config.AuditConfig{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	// A failed reopen keeps writing to the open file
+	if err := repo.Open(config.AuditConfig{Path: filepath.Join(t.TempDir(), "missing", "audit.jsonl")}); err == nil {
+		t.Fatal("open in a missing directory succeeded")
+	}
+	if err := repo.Append(context.Background(), &entity.AuditRecord{Tool: "echo"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"tool":"echo"`) {
+		t.Errorf("the record was not written after a failed reopen: %q", data)
+	}
+
+	// An audit log that lost its file must not drop records silently
+	repo.file.Close()
+	repo.file = nil
+	if err := repo.Append(context.Background(), &entity.AuditRecord{Tool: "echo"}); !errors.Is(err, ErrAuditClosed) {
+		t.Errorf("append to a closed audit log = %v, want %v", err, ErrAuditClosed)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
@@ -24,11 +25,12 @@ func NewConfigRepositoryImpl(filepath string) *ConfigRepositoryImpl {
 	}
 }
 
-// Load loads configuration from file
-func (r *ConfigRepositoryImpl) Load(ctx context.Context) (*config.Config, error) {
-	data, err := os.ReadFile(r.filepath)
+// Load loads configuration from file. An empty path selects the default file.
+// The format is chosen by the file extension: .json, .yaml, .yml or .toml.
+func (r *ConfigRepositoryImpl) Load(ctx context.Context, path string) (*config.Config, error) {
+	data, err := readJSON(r.path(path))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
 	var config config.Config
@@ -39,16 +41,151 @@ func (r *ConfigRepositoryImpl) Load(ctx context.Context) (*config.Config, error)
 	return &config, nil
 }
 
-// Save saves configuration to file
-func (r *ConfigRepositoryImpl) Save(ctx context.Context, config *config.Config) error {
-	data, err := json.MarshalIndent(config, "", "  ")
+// LoadDocument loads the configuration file as a generic JSON document, keeping
+// keys that Config does not define so they can be validated against the schema
+func (r *ConfigRepositoryImpl) LoadDocument(ctx context.Context, path string) (map[string]interface{}, error) {
+	data, err := readJSON(r.path(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	return document, nil
+}
+
+// readJSON reads a configuration file in any supported format and converts it to JSON
+func readJSON(path string) ([]byte, error) {
+	format, err := formatForPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	data, err = format.toJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s config file: %w", format, err)
+	}
+	return data, nil
+}
+
+// Save saves configuration to file. An empty path selects the default file.
+// The format is chosen by the file extension like Load. The file is replaced
+// atomically and the previous version is kept as path.bak.
+func (r *ConfigRepositoryImpl) Save(ctx context.Context, config *config.Config, path string) error {
+	path = r.path(path)
+	format, err := formatForPath(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(r.filepath, data, 0644); err != nil {
+	data, err = format.fromJSON(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode config as %s: %w", format, err)
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic replaces a file by writing a temporary file in the same directory
+// and renaming it over the target, so readers never see a partial file. The previous
+// contents are kept in path.bak.
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+		previous, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read config file for backup: %w", err)
+		}
+		if err := os.WriteFile(path+".bak", previous, mode); err != nil {
+			return fmt.Errorf("failed to write backup: %w", err)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("failed to set config file mode: %w", err)
+	}
 
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
 	return nil
 }
+
+// ImportServers reads the server definitions of another MCP client's configuration file.
+// Both the "mcpServers" key of desktop clients and the "servers" key of editor
+// configurations are accepted; "type" is read as an alias of "transport".
+func (r *ConfigRepositoryImpl) ImportServers(ctx context.Context, path string) (map[string]config.ServerConfig, error) {
+	data, err := readJSON(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document struct {
+		MCPServers map[string]importedServer `json:"mcpServers"`
+		Servers    map[string]importedServer `json:"servers"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	imported := document.MCPServers
+	if imported == nil {
+		imported = document.Servers
+	}
+	if imported == nil {
+		return nil, fmt.Errorf("%s has no mcpServers or servers section", path)
+	}
+
+	servers := make(map[string]config.ServerConfig, len(imported))
+	for name, server := range imported {
+		if server.Transport == "" {
+			server.Transport = server.Type
+		}
+		servers[name] = server.ServerConfig
+	}
+	return servers, nil
+}
+
+// importedServer is a server definition as written by other MCP clients
+type importedServer struct {
+	config.ServerConfig
+	Type string `json:"type,omitempty"`
+}
+
+// path returns the file to use for a request
+func (r *ConfigRepositoryImpl) path(path string) string {
+	if path == "" {
+		return r.filepath
+	}
+	return path
+}
@@ -0,0 +1,127 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// configFormat is the file format of a configuration file
+type configFormat string
+
+const (
+	formatJSON configFormat = "json"
+	formatYAML configFormat = "yaml"
+	formatTOML configFormat = "toml"
+)
+
+// formatForPath selects the configuration format from the file extension
+func formatForPath(path string) (configFormat, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", "":
+		return formatJSON, nil
+	case ".yaml", ".yml":
+		return formatYAML, nil
+	case ".toml":
+		return formatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config file extension %q (use .json, .yaml, .yml or .toml)", ext)
+	}
+}
+
+// toJSON converts a configuration document to JSON so every format is
+// decoded with the same field names and rules
+func (f configFormat) toJSON(data []byte) ([]byte, error) {
+	var document interface{}
+	switch f {
+	case formatYAML:
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+	case formatTOML:
+		if err := toml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+	default:
+		return data, nil
+	}
+
+	// An empty YAML document decodes to nil
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	return json.Marshal(document)
+}
+
+// fromJSON converts a JSON encoded configuration to the format
+func (f configFormat) fromJSON(data []byte) ([]byte, error) {
+	switch f {
+	case formatYAML:
+		// JSON is valid YAML; decoding it into a node keeps the key order
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		resetYAMLStyle(&node)
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case formatTOML:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var document map[string]interface{}
+		if err := dec.Decode(&document); err != nil {
+			return nil, err
+		}
+		return toml.Marshal(normalizeNumbers(document))
+	default:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// resetYAMLStyle switches a node tree decoded from JSON to block style
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// normalizeNumbers turns json.Number values into int64 or float64 so that
+// integers are written to TOML without a fraction
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return value
+}
@@ -0,0 +1,65 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
+)
+
+// watchDebounce coalesces the burst of events an editor produces for one save
+const watchDebounce = 200 * time.Millisecond
+
+// Watch calls onChange after the configuration file at path is written, created,
+// replaced or removed, until ctx is done. The directory is watched so that
+// editors which save by renaming a temporary file are noticed as well.
+func (r *ConfigRepositoryImpl) Watch(ctx context.Context, path string, onChange func()) error {
+	path, err := filepath.Abs(r.path(path))
+	if err != nil {
+		return fmt.Errorf("failed to resolve config path: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", filepath.Dir(path), err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		timer := time.NewTimer(watchDebounce)
+		timer.Stop()
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path || event.Op == fsnotify.Chmod {
+					continue
+				}
+				timer.Reset(watchDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logging.Logger("config").Warn("config watcher error", "error", err)
+			case <-timer.C:
+				onChange()
+			}
+		}
+	}()
+
+	return nil
+}
@@ -1,67 +1,197 @@
 package infrastructure
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"net/url"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/response"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/metrics"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var _ repository.IFMCPRepository = (*MCPRepositoryImpl)(nil)
 
+const (
+	// ProtocolVersion is the MCP protocol version requested by default
+	ProtocolVersion = "2024-11-05"
+	// DefaultRequestTimeout bounds requests whose context has no deadline
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultDialTimeout bounds connection attempts whose context has no deadline
+	DefaultDialTimeout = 10 * time.Second
+)
+
+// MCPOptions configures an MCPRepositoryImpl. Zero values select the defaults.
+type MCPOptions struct {
+	// Dialer opens the transport for a server URL; DialTransport by default
+	Dialer func(ctx context.Context, serverURL string) (Transport, error)
+	// DialTimeout bounds Connect when its context has no deadline
+	DialTimeout time.Duration
+	// RequestTimeout bounds requests whose context has no deadline
+	RequestTimeout time.Duration
+	// ProtocolVersion is sent in the initialize request
+	ProtocolVersion string
+	// Capabilities are the client capabilities sent in the initialize request
+	Capabilities map[string]interface{}
+	// Logger receives diagnostic messages; slog.Default() by default
+	Logger *slog.Logger
+	// TracerProvider creates the spans of connections and requests; the global one by default
+	TracerProvider trace.TracerProvider
+}
+
 // MCPRepositoryImpl implements the MCP repository interface
 type MCPRepositoryImpl struct {
-	conn     *websocket.Conn
-	mu       sync.RWMutex
-	handlers map[string]MessageHandler
+	opts   MCPOptions
+	logger *slog.Logger
+	tracer trace.Tracer
+	conn   Transport
+	// server labels the metrics of the connection; connected is set once a connection was made
+	server    string
+	connected bool
+	mu        sync.RWMutex
+	recorder  *Recorder
+	handlers  map[string]MessageHandler
+	fallback  MessageHandler
+	onClose   func(err error)
+	pending   map[string]chan *entity.Message
+	// tools are the names from the last tools/list; other names are not used as metric labels
+	tools map[string]bool
 }
 
 // MessageHandler is a function type for handling incoming messages
 type MessageHandler func(*entity.Message) error
 
-// NewMCPRepositoryImpl creates a new MCP repository implementation
+// NewMCPRepositoryImpl creates a new MCP repository implementation with the default options
 func NewMCPRepositoryImpl() *MCPRepositoryImpl {
+	return NewMCPRepositoryImplWithOptions(MCPOptions{})
+}
+
+// NewMCPRepositoryImplWithOptions creates a new MCP repository implementation
+func NewMCPRepositoryImplWithOptions(opts MCPOptions) *MCPRepositoryImpl {
+	if opts.Dialer == nil {
+		opts.Dialer = DialTransport
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = DefaultDialTimeout
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = DefaultRequestTimeout
+	}
+	if opts.ProtocolVersion == "" {
+		opts.ProtocolVersion = ProtocolVersion
+	}
+	if opts.Capabilities == nil {
+		opts.Capabilities = make(map[string]interface{})
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.TracerProvider == nil {
+		opts.TracerProvider = otel.GetTracerProvider()
+	}
+
 	return &MCPRepositoryImpl{
+		opts:     opts,
+		logger:   opts.Logger,
+		tracer:   opts.TracerProvider.Tracer(tracing.InstrumentationName),
 		handlers: make(map[string]MessageHandler),
+		pending:  make(map[string]chan *entity.Message),
 	}
 }
 
-// Connect establishes a WebSocket connection to the MCP server
-func (r *MCPRepositoryImpl) Connect(ctx context.Context, serverURL string) error {
-	u, err := url.Parse(serverURL)
-	if err != nil {
-		return fmt.Errorf("invalid URL: %w", err)
+// Connect establishes a connection to the MCP server
+func (r *MCPRepositoryImpl) Connect(ctx context.Context, serverURL string) (err error) {
+	ctx, span := r.startSpan(ctx, "connect", attrServer.String(serverURL))
+	defer func() { endSpan(span, err) }()
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		dial, _ := r.timeouts()
+		ctx, cancel = context.WithTimeout(ctx, dial)
+		defer cancel()
 	}
 
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	transport, err := r.opts.Dialer(ctx, serverURL)
 	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+		return err
 	}
 
-	r.conn = conn
+	r.attach(serverURL, transport)
+	return nil
+}
 
-	// Start listening for messages
-	go r.listen()
+// ConnectServer connects to a server definition from the mcpServers configuration.
+// Plain WebSocket definitions without headers go through the configured Dialer.
+func (r *MCPRepositoryImpl) ConnectServer(ctx context.Context, server config.ServerConfig) (err error) {
+	if server.TransportType() == config.TransportWebSocket && len(server.Headers) == 0 {
+		return r.Connect(ctx, server.URL)
+	}
+
+	ctx, span := r.startSpan(ctx, "connect",
+		attrServer.String(server.Address()), attrTransport.String(server.TransportType()))
+	defer func() { endSpan(span, err) }()
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		dial, _ := r.timeouts()
+		ctx, cancel = context.WithTimeout(ctx, dial)
+		defer cancel()
+	}
 
+	transport, err := DialServer(ctx, server, r.logger)
+	if err != nil {
+		return err
+	}
+
+	r.attach(server.Address(), transport)
 	return nil
 }
 
-// Disconnect closes the WebSocket connection
+// ConnectTransport uses an already opened transport as the connection.
+// Tests use it to talk to a ReplayTransport without a real server.
+func (r *MCPRepositoryImpl) ConnectTransport(transport Transport) {
+	r.attach("", transport)
+}
+
+// attach makes transport the connection to server and starts listening on it
+func (r *MCPRepositoryImpl) attach(server string, transport Transport) {
+	r.mu.Lock()
+	if r.connected && r.server == server {
+		metrics.Reconnects.WithLabelValues(server).Inc()
+	}
+	r.conn = transport
+	r.server = server
+	r.connected = true
+	r.mu.Unlock()
+
+	// Start listening for messages
+	go r.listen(transport)
+}
+
+// Disconnect closes the connection
 func (r *MCPRepositoryImpl) Disconnect() error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	conn := r.conn
+	r.conn = nil
+	r.mu.Unlock()
+
+	r.failPending()
 
-	if r.conn != nil {
-		return r.conn.Close()
+	if conn != nil {
+		return conn.Close()
 	}
 	return nil
 }
@@ -76,38 +206,39 @@ func (r *MCPRepositoryImpl) IsConnected() bool {
 // SendMessage sends a message to the server
 func (r *MCPRepositoryImpl) SendMessage(ctx context.Context, message *entity.Message) error {
 	r.mu.RLock()
-	if r.conn == nil {
-		r.mu.RUnlock()
+	conn := r.conn
+	r.mu.RUnlock()
+	if conn == nil {
 		return fmt.Errorf("not connected")
 	}
-	r.mu.RUnlock()
+
+	if message.JSONRPC == "" {
+		message.JSONRPC = entity.JSONRPCVersion
+	}
 
 	data, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	return r.conn.WriteMessage(websocket.TextMessage, data)
+	r.record(DirectionSend, data)
+	return conn.WriteFrame(ctx, data)
 }
 
 // ReceiveMessage receives a message from the server
 func (r *MCPRepositoryImpl) ReceiveMessage(ctx context.Context) (*entity.Message, error) {
 	r.mu.RLock()
-	if r.conn == nil {
-		r.mu.RUnlock()
-		return nil, fmt.Errorf("not connected")
-	}
+	conn := r.conn
 	r.mu.RUnlock()
-
-	// Set read deadline
-	if err := r.conn.SetReadDeadline(time.Now().Add(30 * time.Second)); err != nil {
-		return nil, fmt.Errorf("failed to set read deadline: %w", err)
+	if conn == nil {
+		return nil, fmt.Errorf("not connected")
 	}
 
-	_, data, err := r.conn.ReadMessage()
+	data, err := conn.ReadFrame()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read message: %w", err)
 	}
+	r.record(DirectionReceive, data)
 
 	var msg entity.Message
 	if err := json.Unmarshal(data, &msg); err != nil {
@@ -117,6 +248,20 @@ func (r *MCPRepositoryImpl) ReceiveMessage(ctx context.Context) (*entity.Message
 	return &msg, nil
 }
 
+// Request sends an arbitrary request and returns the raw result
+func (r *MCPRepositoryImpl) Request(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+	var payload interface{}
+	if len(params) > 0 {
+		payload = params
+	}
+
+	var result json.RawMessage
+	if err := r.request(ctx, method, payload, &result); err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", method, err)
+	}
+	return result, nil
+}
+
 // Initialize sends an initialize request to the server
 func (r *MCPRepositoryImpl) Initialize(ctx context.Context, clientInfo entity.ClientInfo) (*response.InitializeResponse, error) {
 	req := struct {
@@ -124,159 +269,429 @@ func (r *MCPRepositoryImpl) Initialize(ctx context.Context, clientInfo entity.Cl
 		Capabilities    map[string]interface{} `json:"capabilities"`
 		ClientInfo      entity.ClientInfo      `json:"clientInfo"`
 	}{
-		ProtocolVersion: "2024-11-05",
-		Capabilities:    make(map[string]interface{}),
+		ProtocolVersion: r.opts.ProtocolVersion,
+		Capabilities:    r.opts.Capabilities,
 		ClientInfo:      clientInfo,
 	}
 
-	reqData, err := json.Marshal(req)
+	var resp response.InitializeResponse
+	if err := r.request(ctx, "initialize", req, &resp); err != nil {
+		return nil, fmt.Errorf("initialize request failed: %w", err)
+	}
+
+	// Tell the server the handshake is complete
+	if err := r.SendMessage(ctx, &entity.Message{Method: "notifications/initialized"}); err != nil {
+		return nil, fmt.Errorf("failed to send initialized notification: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// Ping checks that the server is responsive
+func (r *MCPRepositoryImpl) Ping(ctx context.Context) error {
+	if err := r.request(ctx, "ping", nil, nil); err != nil {
+		return fmt.Errorf("ping request failed: %w", err)
+	}
+	return nil
+}
+
+// ListTools retrieves available tools from the server
+func (r *MCPRepositoryImpl) ListTools(ctx context.Context) ([]entity.Tool, error) {
+	tools := []entity.Tool{}
+	cursor := ""
+	for {
+		var resp response.ListToolsResponse
+		if err := r.request(ctx, "tools/list", cursorParams(cursor), &resp); err != nil {
+			return nil, fmt.Errorf("tools/list request failed: %w", err)
+		}
+		tools = append(tools, resp.Tools...)
+		if resp.NextCursor == "" {
+			names := make(map[string]bool, len(tools))
+			for _, tool := range tools {
+				names[tool.Name] = true
+			}
+			r.mu.Lock()
+			r.tools = names
+			r.mu.Unlock()
+			return tools, nil
+		}
+		cursor = resp.NextCursor
+	}
+}
+
+// CallTool executes a tool on the server
+func (r *MCPRepositoryImpl) CallTool(ctx context.Context, toolCall entity.ToolCall) (*entity.ToolResult, error) {
+	result := &entity.ToolResult{
+		Content: []entity.Content{},
+	}
+	start := time.Now()
+	err := r.request(ctx, "tools/call", toolCall, result, attrToolName.String(toolCall.Name))
+	metrics.ToolCallDuration.WithLabelValues(r.toolLabel(toolCall.Name)).Observe(time.Since(start).Seconds())
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal initialize request: %w", err)
+		return nil, fmt.Errorf("tools/call request failed: %w", err)
 	}
+	return result, nil
+}
 
-	msg := &entity.Message{
-		ID:     uuid.New().String(),
-		Method: "initialize",
-		Params: reqData,
+// toolLabel is the metric label of a tool. Names the server did not list come
+// from callers and would make the label set unbounded, so they share one label.
+func (r *MCPRepositoryImpl) toolLabel(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.tools[name] {
+		return name
 	}
+	return "unknown"
+}
 
-	// Send the message
-	if err := r.SendMessage(ctx, msg); err != nil {
-		return nil, fmt.Errorf("failed to send initialize message: %w", err)
+// notificationMethods are the notifications the protocol defines for servers to send
+var notificationMethods = map[string]bool{
+	"notifications/cancelled":              true,
+	"notifications/progress":               true,
+	"notifications/message":                true,
+	"notifications/resources/updated":      true,
+	"notifications/resources/list_changed": true,
+	"notifications/tools/list_changed":     true,
+	"notifications/prompts/list_changed":   true,
+}
+
+// notificationLabel is the metric label of a notification method. Servers may
+// send any method, so the ones the protocol does not define share one label.
+func notificationLabel(method string) string {
+	if notificationMethods[method] {
+		return method
 	}
+	return "other"
+}
 
-	// Wait for response (simplified implementation)
-	time.Sleep(100 * time.Millisecond)
+// ListResources retrieves available resources from the server
+func (r *MCPRepositoryImpl) ListResources(ctx context.Context) ([]entity.Resource, error) {
+	resources := []entity.Resource{}
+	cursor := ""
+	for {
+		var resp response.ListResourcesResponse
+		if err := r.request(ctx, "resources/list", cursorParams(cursor), &resp); err != nil {
+			return nil, fmt.Errorf("resources/list request failed: %w", err)
+		}
+		resources = append(resources, resp.Resources...)
+		if resp.NextCursor == "" {
+			return resources, nil
+		}
+		cursor = resp.NextCursor
+	}
+}
+
+// ReadResource reads the contents of a resource from the server
+func (r *MCPRepositoryImpl) ReadResource(ctx context.Context, uri string) ([]entity.ResourceContents, error) {
+	params := map[string]string{"uri": uri}
 
-	// Return a mock response for now
-	return &response.InitializeResponse{
-		ProtocolVersion: "2024-11-05",
-		Capabilities: response.ServerCapabilities{
-			Tools: make(map[string]interface{}),
-		},
-		ServerInfo: entity.ServerInfo{
-			Name:    "mock-mcp-server",
-			Version: "1.0.0",
-		},
-	}, nil
+	var resp response.ReadResourceResponse
+	if err := r.request(ctx, "resources/read", params, &resp); err != nil {
+		return nil, fmt.Errorf("resources/read request failed: %w", err)
+	}
+	return resp.Contents, nil
 }
 
-// ListTools retrieves available tools from the server
-func (r *MCPRepositoryImpl) ListTools(ctx context.Context) ([]entity.Tool, error) {
-	msg := &entity.Message{
-		ID:     uuid.New().String(),
-		Method: "tools/list",
+// ListPrompts retrieves available prompts from the server
+func (r *MCPRepositoryImpl) ListPrompts(ctx context.Context) ([]entity.Prompt, error) {
+	prompts := []entity.Prompt{}
+	cursor := ""
+	for {
+		var resp response.ListPromptsResponse
+		if err := r.request(ctx, "prompts/list", cursorParams(cursor), &resp); err != nil {
+			return nil, fmt.Errorf("prompts/list request failed: %w", err)
+		}
+		prompts = append(prompts, resp.Prompts...)
+		if resp.NextCursor == "" {
+			return prompts, nil
+		}
+		cursor = resp.NextCursor
 	}
+}
 
-	// Send the message
-	if err := r.SendMessage(ctx, msg); err != nil {
-		return nil, fmt.Errorf("failed to send tools/list message: %w", err)
+// GetPrompt renders a prompt on the server
+func (r *MCPRepositoryImpl) GetPrompt(ctx context.Context, request entity.PromptRequest) (*entity.PromptResult, error) {
+	var result entity.PromptResult
+	if err := r.request(ctx, "prompts/get", request, &result); err != nil {
+		return nil, fmt.Errorf("prompts/get request failed: %w", err)
 	}
+	return &result, nil
+}
 
-	// Return empty list for now
-	return []entity.Tool{}, nil
+// RegisterHandler registers a message handler for a specific method
+func (r *MCPRepositoryImpl) RegisterHandler(method string, handler MessageHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[method] = handler
 }
 
-// CallTool executes a tool on the server
-func (r *MCPRepositoryImpl) CallTool(ctx context.Context, toolCall entity.ToolCall) (*entity.ToolResult, error) {
-	reqData, err := json.Marshal(toolCall)
+// StartRecording writes every frame sent and received from now on to a file
+func (r *MCPRepositoryImpl) StartRecording(path string) error {
+	recorder, err := NewRecorder(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal tool call: %w", err)
+		return err
+	}
+
+	r.mu.Lock()
+	previous := r.recorder
+	r.recorder = recorder
+	r.mu.Unlock()
+
+	if previous != nil {
+		return previous.Close()
+	}
+	return nil
+}
+
+// StopRecording stops recording and closes the recording file
+func (r *MCPRepositoryImpl) StopRecording() error {
+	r.mu.Lock()
+	recorder := r.recorder
+	r.recorder = nil
+	r.mu.Unlock()
+
+	if recorder == nil {
+		return nil
+	}
+	return recorder.Close()
+}
+
+// SetDefaultHandler sets the handler for server messages that have no registered handler
+func (r *MCPRepositoryImpl) SetDefaultHandler(handler func(*entity.Message) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = handler
+}
+
+// SetTimeouts changes the dial and request timeouts; zero keeps the current value
+func (r *MCPRepositoryImpl) SetTimeouts(dial, request time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if dial > 0 {
+		r.opts.DialTimeout = dial
+	}
+	if request > 0 {
+		r.opts.RequestTimeout = request
+	}
+}
+
+// timeouts returns the current dial and request timeouts
+func (r *MCPRepositoryImpl) timeouts() (dial, request time.Duration) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.opts.DialTimeout, r.opts.RequestTimeout
+}
+
+// SetCloseHandler sets the handler called when the server closes the connection
+func (r *MCPRepositoryImpl) SetCloseHandler(handler func(err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onClose = handler
+}
+
+// request sends a request and waits for the matching response.
+// The result is decoded into result unless it is nil. attrs are added to the span of the request.
+func (r *MCPRepositoryImpl) request(ctx context.Context, method string, params interface{}, result interface{}, attrs ...attribute.KeyValue) (err error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		_, request := r.timeouts()
+		ctx, cancel = context.WithTimeout(ctx, request)
+		defer cancel()
 	}
 
 	msg := &entity.Message{
 		ID:     uuid.New().String(),
-		Method: "tools/call",
-		Params: reqData,
+		Method: method,
+	}
+
+	r.mu.RLock()
+	server := r.server
+	r.mu.RUnlock()
+	ctx, span := r.startSpan(ctx, method, append(attrs,
+		attrMethod.String(method), attrServer.String(server), attrRequestID.String(msg.ID))...)
+	defer func() { endSpan(span, err) }()
+
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal params: %w", err)
+		}
+		msg.Params = data
 	}
+	msg.Params = withTraceMeta(ctx, msg.Params)
 
-	// Send the message
+	ch := make(chan *entity.Message, 1)
+	r.mu.Lock()
+	r.pending[msg.ID] = ch
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, msg.ID)
+		r.mu.Unlock()
+	}()
+
+	inFlight := metrics.InFlight.WithLabelValues(server)
+	inFlight.Inc()
+	start := time.Now()
+	outcome := metrics.OutcomeTransport
+	defer func() {
+		inFlight.Dec()
+		metrics.Requests.WithLabelValues(method, server, outcome).Inc()
+		metrics.RequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	}()
+
+	logger := r.logger.With("request_id", msg.ID, "method", method)
+	logger.Debug("sending request")
 	if err := r.SendMessage(ctx, msg); err != nil {
-		return nil, fmt.Errorf("failed to send tools/call message: %w", err)
+		return fmt.Errorf("failed to send message: %w", err)
 	}
 
-	// Return empty result for now
-	return &entity.ToolResult{
-		Content: []entity.Content{},
-		IsError: false,
-	}, nil
+	select {
+	case <-ctx.Done():
+		logger.Debug("request abandoned", "error", ctx.Err(), "elapsed", time.Since(start))
+		outcome = metrics.OutcomeCanceled
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			outcome = metrics.OutcomeTimeout
+		}
+		return ctx.Err()
+	case resp, ok := <-ch:
+		if !ok {
+			return fmt.Errorf("connection closed while waiting for response")
+		}
+		logger.Debug("received response", "elapsed", time.Since(start))
+		outcome = metrics.OutcomeError
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) > 0 {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("failed to unmarshal result: %w", err)
+			}
+		}
+		outcome = metrics.OutcomeSuccess
+		return nil
+	}
 }
 
-// RegisterHandler registers a message handler for a specific method
-func (r *MCPRepositoryImpl) RegisterHandler(method string, handler MessageHandler) {
+// record counts the bytes of a frame and writes it to the active recording, if any
+func (r *MCPRepositoryImpl) record(direction string, data []byte) {
+	r.mu.RLock()
+	recorder := r.recorder
+	server := r.server
+	r.mu.RUnlock()
+
+	if direction == DirectionSend {
+		metrics.BytesSent.WithLabelValues(server).Add(float64(len(data)))
+	} else {
+		metrics.BytesReceived.WithLabelValues(server).Add(float64(len(data)))
+	}
+
+	if recorder != nil {
+		if err := recorder.Record(direction, data); err != nil {
+			r.logger.Warn("failed to record frame", "error", err)
+		}
+	}
+
+	if logging.Frames() && r.logger.Enabled(context.Background(), slog.LevelDebug) {
+		r.logger.Debug("frame", "direction", direction, "data", string(bytes.TrimSpace(data)))
+	}
+}
+
+// failPending unblocks every request waiting for a response
+func (r *MCPRepositoryImpl) failPending() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.handlers[method] = handler
+	for id, ch := range r.pending {
+		close(ch)
+		delete(r.pending, id)
+	}
 }
 
 // listen listens for incoming messages
-func (r *MCPRepositoryImpl) listen() {
+func (r *MCPRepositoryImpl) listen(conn Transport) {
 	for {
-		r.mu.RLock()
-		if r.conn == nil {
-			r.mu.RUnlock()
-			return
-		}
-		r.mu.RUnlock()
-
-		_, data, err := r.conn.ReadMessage()
+		data, err := conn.ReadFrame()
 		if err != nil {
-			log.Printf("Error reading message: %v", err)
+			r.mu.Lock()
+			closed := r.conn != conn
+			if !closed {
+				r.conn = nil
+			}
+			onClose := r.onClose
+			r.mu.Unlock()
+			// After Disconnect the pending requests were already failed, and
+			// after a reconnect they belong to the new connection
+			if !closed {
+				r.failPending()
+				r.logger.Error("failed to read message", "error", err)
+				if onClose != nil {
+					onClose(err)
+				}
+			}
 			return
 		}
+		r.record(DirectionReceive, data)
 
 		var msg entity.Message
 		if err := json.Unmarshal(data, &msg); err != nil {
-			log.Printf("Error unmarshaling message: %v", err)
+			r.logger.Warn("failed to decode message", "error", err)
 			continue
 		}
 
 		// Handle the message
 		if err := r.handleMessage(&msg); err != nil {
-			log.Printf("Error handling message: %v", err)
+			r.logger.Warn("failed to handle message", "method", msg.Method, "request_id", msg.ID, "error", err)
 		}
 	}
 }
 
 // handleMessage handles an incoming message
 func (r *MCPRepositoryImpl) handleMessage(msg *entity.Message) error {
-	r.mu.RLock()
+	r.mu.Lock()
+	if ch, waiting := r.pending[msg.ID]; waiting && msg.ID != "" {
+		delete(r.pending, msg.ID)
+		r.mu.Unlock()
+		ch <- msg
+		return nil
+	}
 	handler, exists := r.handlers[msg.Method]
-	r.mu.RUnlock()
+	fallback := r.fallback
+	server := r.server
+	r.mu.Unlock()
+
+	if msg.ID == "" && msg.Method != "" {
+		metrics.Notifications.WithLabelValues(notificationLabel(msg.Method), server).Inc()
+	}
 
 	if exists {
 		return handler(msg)
 	}
 
 	// Default handling for common methods
-	switch msg.Method {
-	case "initialize":
-		return r.handleInitialize(msg)
-	case "tools/list":
-		return r.handleToolsList(msg)
-	case "tools/call":
-		return r.handleToolsCall(msg)
+	switch {
+	case msg.Method == "ping":
+		return r.handlePing(msg)
+	case fallback != nil:
+		return fallback(msg)
 	default:
-		log.Printf("Unhandled method: %s", msg.Method)
+		r.logger.Info("unhandled method", "method", msg.Method, "request_id", msg.ID)
 	}
 
 	return nil
 }
 
-// handleInitialize handles initialize responses
-func (r *MCPRepositoryImpl) handleInitialize(msg *entity.Message) error {
-	log.Printf("Received initialize response")
-	return nil
+// handlePing answers ping requests from the server
+func (r *MCPRepositoryImpl) handlePing(msg *entity.Message) error {
+	return r.SendMessage(context.Background(), &entity.Message{
+		ID:     msg.ID,
+		Result: json.RawMessage("{}"),
+	})
 }
 
-// handleToolsList handles tools/list responses
-func (r *MCPRepositoryImpl) handleToolsList(msg *entity.Message) error {
-	log.Printf("Received tools list")
-	return nil
-}
-
-// handleToolsCall handles tools/call requests
-func (r *MCPRepositoryImpl) handleToolsCall(msg *entity.Message) error {
-	log.Printf("Received tool call request")
-	return nil
+// cursorParams builds pagination params for list requests
+func cursorParams(cursor string) interface{} {
+	if cursor == "" {
+		return nil
+	}
+	return map[string]string{"cursor": cursor}
 }
@@ -1,8 +1,15 @@
 package infrastructure
 
-import "github.com/google/wire"
+import (
+	"github.com/google/wire"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
+)
 
 var InfrastructureSet = wire.NewSet(
-	NewMCPRepositoryImpl,
 	NewConfigRepositoryImpl,
+	wire.Bind(new(repository.IFConfigRepository), new(*ConfigRepositoryImpl)),
+	NewSecretRepositoryImpl,
+	wire.Bind(new(repository.IFSecretRepository), new(*SecretRepositoryImpl)),
+	NewAuditRepositoryImpl,
+	wire.Bind(new(repository.IFAuditRepository), new(*AuditRepositoryImpl)),
 )
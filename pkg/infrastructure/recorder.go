@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Frame directions stored in a recording
+const (
+	DirectionSend    = "send"
+	DirectionReceive = "recv"
+)
+
+// RecordedFrame is one line of a traffic recording
+type RecordedFrame struct {
+	Time      time.Time       `json:"time"`
+	Direction string          `json:"direction"`
+	Frame     json.RawMessage `json:"frame"`
+}
+
+// Recorder appends frames to a JSONL recording file
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewRecorder creates a recorder that writes to path, truncating any previous recording
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file: %w", err)
+	}
+
+	return &Recorder{
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+// Record writes a single frame with the current time
+func (r *Recorder) Record(direction string, data []byte) error {
+	if !json.Valid(data) {
+		// Keep malformed frames so that the recording shows what was on the wire
+		quoted, err := json.Marshal(string(data))
+		if err != nil {
+			return err
+		}
+		data = quoted
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(RecordedFrame{
+		Time:      time.Now().UTC(),
+		Direction: direction,
+		Frame:     data,
+	})
+}
+
+// Close closes the recording file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// LoadRecording reads all frames from a recording file
+func LoadRecording(path string) ([]RecordedFrame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file: %w", err)
+	}
+	defer file.Close()
+
+	var frames []RecordedFrame
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var frame RecordedFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("invalid recording on line %d: %w", line, err)
+		}
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording file: %w", err)
+	}
+	return frames, nil
+}
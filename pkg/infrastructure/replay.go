@@ -0,0 +1,225 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+var _ Transport = (*ReplayTransport)(nil)
+
+// ReplayTransport serves recorded responses instead of talking to a server.
+// Each request is matched to an unused recorded request with the same method
+// and params, ignoring _meta, and answered with the recorded response under the
+// new request ID. initialize matches by method alone since its params describe
+// the client rather than what is asked. Server messages recorded between the request and its
+// response are delivered too.
+type ReplayTransport struct {
+	mu        sync.Mutex
+	frames    []RecordedFrame
+	used      []bool
+	queue     [][]byte
+	ready     chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// OpenReplayTransport loads a recording file and serves it
+func OpenReplayTransport(path string) (*ReplayTransport, error) {
+	frames, err := LoadRecording(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayTransport(frames), nil
+}
+
+// NewReplayTransport serves the given recorded frames
+func NewReplayTransport(frames []RecordedFrame) *ReplayTransport {
+	return &ReplayTransport{
+		frames: frames,
+		used:   make([]bool, len(frames)),
+		ready:  make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+}
+
+// WriteFrame implements Transport
+func (t *ReplayTransport) WriteFrame(ctx context.Context, data []byte) error {
+	select {
+	case <-t.closed:
+		return fmt.Errorf("replay transport closed")
+	default:
+	}
+
+	var msg entity.Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return fmt.Errorf("failed to unmarshal frame: %w", err)
+	}
+
+	// Notifications and responses from the client need no answer
+	if msg.ID == "" || msg.Method == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	index := t.match(&msg)
+	if index < 0 {
+		return t.pushError(msg.ID, fmt.Sprintf("no recorded response for %s with these params", msg.Method))
+	}
+	t.used[index] = true
+	recordedID := frameID(t.frames[index].Frame)
+
+	for i := index + 1; i < len(t.frames); i++ {
+		frame := t.frames[i]
+		if t.used[i] || frame.Direction != DirectionReceive {
+			continue
+		}
+
+		var recorded entity.Message
+		if err := json.Unmarshal(frame.Frame, &recorded); err != nil {
+			continue
+		}
+
+		switch {
+		case recorded.ID == recordedID:
+			t.used[i] = true
+			response, err := withID(frame.Frame, msg.ID)
+			if err != nil {
+				return err
+			}
+			t.push(response)
+			return nil
+		case recorded.ID == "" || (recorded.Method != "" && recorded.Result == nil && recorded.Error == nil):
+			// Server notifications and requests are delivered as recorded
+			t.used[i] = true
+			t.push(frame.Frame)
+		}
+	}
+
+	return t.pushError(msg.ID, fmt.Sprintf("recording has no response to %s", msg.Method))
+}
+
+// ReadFrame implements Transport
+func (t *ReplayTransport) ReadFrame() ([]byte, error) {
+	for {
+		t.mu.Lock()
+		if len(t.queue) > 0 {
+			data := t.queue[0]
+			t.queue = t.queue[1:]
+			t.mu.Unlock()
+			return data, nil
+		}
+		t.mu.Unlock()
+
+		select {
+		case <-t.ready:
+		case <-t.closed:
+			return nil, io.EOF
+		}
+	}
+}
+
+// Close implements Transport
+func (t *ReplayTransport) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	return nil
+}
+
+// match returns the index of the first unused recorded request matching msg, or -1.
+// Callers must hold t.mu.
+func (t *ReplayTransport) match(msg *entity.Message) int {
+	for i, frame := range t.frames {
+		if t.used[i] || frame.Direction != DirectionSend {
+			continue
+		}
+
+		var recorded entity.Message
+		if err := json.Unmarshal(frame.Frame, &recorded); err != nil || recorded.Method != msg.Method || recorded.ID == "" {
+			continue
+		}
+		if msg.Method == "initialize" || sameParams(recorded.Params, msg.Params) {
+			return i
+		}
+	}
+	return -1
+}
+
+// push queues a frame for ReadFrame. Callers must hold t.mu.
+func (t *ReplayTransport) push(data []byte) {
+	t.queue = append(t.queue, data)
+	select {
+	case t.ready <- struct{}{}:
+	default:
+	}
+}
+
+// pushError queues a JSON-RPC error response. Callers must hold t.mu.
+func (t *ReplayTransport) pushError(id, message string) error {
+	data, err := json.Marshal(&entity.Message{
+		JSONRPC: entity.JSONRPCVersion,
+		ID:      id,
+		Error:   &entity.Error{Code: -32603, Message: message},
+	})
+	if err != nil {
+		return err
+	}
+	t.push(data)
+	return nil
+}
+
+// frameID returns the id of a raw frame
+func frameID(frame json.RawMessage) string {
+	var msg entity.Message
+	if err := json.Unmarshal(frame, &msg); err != nil {
+		return ""
+	}
+	return msg.ID
+}
+
+// withID returns a copy of a raw frame with its id replaced
+func withID(frame json.RawMessage, id string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(frame, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recorded frame: %w", err)
+	}
+
+	encoded, err := json.Marshal(id)
+	if err != nil {
+		return nil, err
+	}
+	fields["id"] = encoded
+	return json.Marshal(fields)
+}
+
+// sameParams reports whether two params documents are semantically equal. _meta
+// is left out since it carries values such as trace context that change on every call.
+func sameParams(a, b json.RawMessage) bool {
+	va, okA := decodeParams(a)
+	vb, okB := decodeParams(b)
+	return okA && okB && reflect.DeepEqual(va, vb)
+}
+
+// decodeParams decodes params without _meta; absent and empty params are both nil
+func decodeParams(data json.RawMessage) (interface{}, bool) {
+	if len(data) == 0 {
+		return nil, true
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, false
+	}
+	if object, isObject := v.(map[string]interface{}); isObject {
+		delete(object, "_meta")
+		if len(object) == 0 {
+			return nil, true
+		}
+	}
+	return v, true
+}
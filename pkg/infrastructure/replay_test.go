@@ -0,0 +1,48 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// replayCall writes a request to the transport and returns the frame it answers with
+func replayCall(t *testing.T, transport *ReplayTransport, request string) string {
+	t.Helper()
+	if err := transport.WriteFrame(context.Background(), []byte(request)); err != nil {
+		t.Fatal(err)
+	}
+	frame, err := transport.ReadFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(frame)
+}
+
+func TestReplayMatchesParams(t *testing.T) {
+	frames := []RecordedFrame{
+		{Direction: DirectionSend, Frame: json.RawMessage(`{"jsonrpc":"2.0","id":"1","method":"tools/call","params":{"name":"add","arguments":{"a":1,"b":2},"_meta":{"traceparent":"00-a-b-01"}}}`)},
+		{Direction: DirectionReceive, Frame: json.RawMessage(`{"jsonrpc":"2.0","id":"1","result":{"structuredContent":{"sum":3}}}`)},
+	}
+
+	tests := []struct {
+		name    string
+		request string
+		want    string
+	}{
+		{"different arguments", `{"jsonrpc":"2.0","id":"x","method":"tools/call","params":{"name":"add","arguments":{"a":5,"b":2}}}`, "no recorded response for tools/call"},
+		{"other method", `{"jsonrpc":"2.0","id":"x","method":"tools/list"}`, "no recorded response for tools/list"},
+		{"same arguments, other _meta", `{"jsonrpc":"2.0","id":"x","method":"tools/call","params":{"arguments":{"b":2,"a":1},"name":"add","_meta":{"traceparent":"00-c-d-01"}}}`, `"sum":3`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := NewReplayTransport(frames)
+			defer transport.Close()
+			got := replayCall(t, transport, tt.request)
+			if !strings.Contains(got, tt.want) || !strings.Contains(got, `"id":"x"`) {
+				t.Errorf("response = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
+)
+
+var _ repository.IFSecretRepository = (*SecretRepositoryImpl)(nil)
+
+// secretCommandTimeout bounds how long a ${cmd:...} reference may run
+const secretCommandTimeout = 10 * time.Second
+
+// SecretRepositoryImpl reads secrets from environment variables, files and commands
+type SecretRepositoryImpl struct{}
+
+// NewSecretRepositoryImpl creates a new secret repository implementation
+func NewSecretRepositoryImpl() *SecretRepositoryImpl {
+	return &SecretRepositoryImpl{}
+}
+
+// Resolve returns the value of a reference. File contents and command output
+// lose their trailing newline; commands run through sh -c.
+func (r *SecretRepositoryImpl) Resolve(ctx context.Context, kind, reference string) (string, error) {
+	switch kind {
+	case config.SecretEnv:
+		value, ok := os.LookupEnv(reference)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", reference)
+		}
+		return value, nil
+	case config.SecretFile:
+		data, err := os.ReadFile(reference)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case config.SecretCmd:
+		return runSecretCommand(ctx, reference)
+	default:
+		return "", fmt.Errorf("unknown secret kind %q", kind)
+	}
+}
+
+// runSecretCommand runs a command and returns its standard output
+func runSecretCommand(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, secretCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
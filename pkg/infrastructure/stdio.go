@@ -0,0 +1,136 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+)
+
+// stdioShutdownTimeout is how long a server process may take to exit after its stdin is closed
+const stdioShutdownTimeout = 5 * time.Second
+
+// stdioTransport talks to a server process over newline delimited JSON on stdin and stdout
+type stdioTransport struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stdout    *bufio.Reader
+	stdoutR   *os.File
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	done      chan struct{}
+	waitErr   error
+}
+
+// StartStdioTransport starts the command of a server definition. The process
+// inherits the environment with the server's env added; its stderr goes to logger.
+func StartStdioTransport(server config.ServerConfig, logger *slog.Logger) (Transport, error) {
+	cmd := exec.Command(server.Command, server.Args...)
+	cmd.Dir = server.Cwd
+	cmd.Env = os.Environ()
+	for key, value := range server.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	cmd.Stderr = &logWriter{logger: logger.With("command", server.Command)}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	// StdoutPipe would be closed by Wait while frames are still being read,
+	// so the transport owns the read end of its own pipe
+	stdout, stdoutW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout = stdoutW
+	err = cmd.Start()
+	stdoutW.Close()
+	if err != nil {
+		stdout.Close()
+		return nil, fmt.Errorf("failed to start %s: %w", server.Command, err)
+	}
+
+	t := &stdioTransport{
+		cmd:     cmd,
+		stdin:   stdin,
+		stdout:  bufio.NewReaderSize(stdout, 64*1024),
+		stdoutR: stdout,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		t.waitErr = cmd.Wait()
+		close(t.done)
+	}()
+	return t, nil
+}
+
+// WriteFrame implements Transport
+func (t *stdioTransport) WriteFrame(ctx context.Context, data []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stdin.Write(append(bytes.TrimSpace(data), '\n')); err != nil {
+		return fmt.Errorf("failed to write to server process: %w", err)
+	}
+	return nil
+}
+
+// ReadFrame implements Transport
+func (t *stdioTransport) ReadFrame() ([]byte, error) {
+	for {
+		line, err := t.stdout.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return line, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close closes stdin so the server can exit, and kills it if it does not.
+// Its stdout is closed once it has exited, which ends a pending ReadFrame.
+func (t *stdioTransport) Close() error {
+	t.closeOnce.Do(func() {
+		t.stdin.Close()
+		select {
+		case <-t.done:
+		case <-time.After(stdioShutdownTimeout):
+			t.cmd.Process.Kill()
+			<-t.done
+		}
+		t.stdoutR.Close()
+	})
+	return nil
+}
+
+// logWriter forwards the lines written to it to a logger
+type logWriter struct {
+	logger *slog.Logger
+	mu     sync.Mutex
+	buf    []byte
+}
+
+// Write implements io.Writer
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.logger.Info("server stderr", "line", string(bytes.TrimRight(w.buf[:i], "\r")))
+		w.buf = w.buf[i+1:]
+	}
+}
@@ -0,0 +1,39 @@
+package infrastructure
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os/exec"
+	"testing"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+)
+
+func TestStdioReadsFramesAfterExit(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	server := config.ServerConfig{Command: "sh", Args: []string{"-c", `echo '{"jsonrpc":"2.0","method":"a"}'; echo '{"jsonrpc":"2.0","method":"b"}'`}}
+	transport, err := StartStdioTransport(server, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer transport.Close()
+
+	// The process has exited before anything is read
+	<-transport.(*stdioTransport).done
+
+	for _, want := range []string{`{"jsonrpc":"2.0","method":"a"}`, `{"jsonrpc":"2.0","method":"b"}`} {
+		frame, err := transport.ReadFrame()
+		if err != nil {
+			t.Fatalf("ReadFrame() error = %v, want %s", err, want)
+		}
+		if string(frame) != want {
+			t.Errorf("ReadFrame() = %s, want %s", frame, want)
+		}
+	}
+	if _, err := transport.ReadFrame(); !errors.Is(err, io.EOF) {
+		t.Errorf("ReadFrame() error = %v, want EOF", err)
+	}
+}
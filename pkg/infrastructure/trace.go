@@ -0,0 +1,83 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys
+const (
+	attrServer       = attribute.Key("server.address")
+	attrTransport    = attribute.Key("mcp.transport")
+	attrMethod       = attribute.Key("mcp.method.name")
+	attrRequestID    = attribute.Key("jsonrpc.request.id")
+	attrToolName     = attribute.Key("gen_ai.tool.name")
+	attrErrorCode    = attribute.Key("rpc.jsonrpc.error_code")
+	attrErrorMessage = attribute.Key("rpc.jsonrpc.error_message")
+)
+
+// traceContext is the W3C trace context propagator used for the _meta of requests
+var traceContext = propagation.TraceContext{}
+
+// startSpan starts a client span of the repository's tracer
+func (r *MCPRepositoryImpl) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return r.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records the outcome of an operation on its span and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		var rpcErr *entity.Error
+		if errors.As(err, &rpcErr) {
+			span.SetAttributes(attrErrorCode.Int(rpcErr.Code), attrErrorMessage.String(rpcErr.Message))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// withTraceMeta adds the trace context of ctx to the _meta object of request
+// params so that the server can continue the trace. Params that are not a JSON
+// object are returned unchanged.
+func withTraceMeta(ctx context.Context, params json.RawMessage) json.RawMessage {
+	carrier := propagation.MapCarrier{}
+	traceContext.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return params
+	}
+
+	object := map[string]json.RawMessage{}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &object); err != nil || object == nil {
+			return params
+		}
+	}
+	meta := map[string]interface{}{}
+	if raw, ok := object["_meta"]; ok {
+		if err := json.Unmarshal(raw, &meta); err != nil || meta == nil {
+			return params
+		}
+	}
+	for key, value := range carrier {
+		meta[key] = value
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return params
+	}
+	object["_meta"] = data
+	data, err = json.Marshal(object)
+	if err != nil {
+		return params
+	}
+	return data
+}
@@ -0,0 +1,96 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+)
+
+// Transport carries raw JSON-RPC frames between the client and a server
+type Transport interface {
+	WriteFrame(ctx context.Context, data []byte) error
+	ReadFrame() ([]byte, error)
+	Close() error
+}
+
+// replayScheme selects the replay transport, e.g. replay://testdata/session.jsonl
+const replayScheme = "replay://"
+
+// DialTransport opens a transport for the given server URL.
+// ws:// and wss:// URLs use WebSocket; replay:// URLs serve a recorded session.
+func DialTransport(ctx context.Context, serverURL string) (Transport, error) {
+	return dialURL(ctx, serverURL, nil)
+}
+
+// DialServer opens a transport for a server definition: stdio servers are started
+// as child processes and remote servers are dialed with their headers.
+// SSE and streamable HTTP transports are not supported.
+func DialServer(ctx context.Context, server config.ServerConfig, logger *slog.Logger) (Transport, error) {
+	switch transport := server.TransportType(); transport {
+	case config.TransportStdio:
+		if server.Command == "" {
+			return nil, fmt.Errorf("stdio server has no command")
+		}
+		return StartStdioTransport(server, logger)
+	case config.TransportWebSocket, "ws":
+		if server.URL == "" {
+			return nil, fmt.Errorf("websocket server has no url")
+		}
+		header := make(http.Header, len(server.Headers))
+		for key, value := range server.Headers {
+			header.Set(key, value)
+		}
+		return dialURL(ctx, server.URL, header)
+	default:
+		return nil, fmt.Errorf("transport %q is not supported (use stdio or websocket)", transport)
+	}
+}
+
+// dialURL opens a WebSocket or replay transport
+func dialURL(ctx context.Context, serverURL string, header http.Header) (Transport, error) {
+	if strings.HasPrefix(serverURL, replayScheme) {
+		return OpenReplayTransport(strings.TrimPrefix(serverURL, replayScheme))
+	}
+
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	return &websocketTransport{conn: conn}, nil
+}
+
+// websocketTransport sends each frame as a WebSocket text message
+type websocketTransport struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+// WriteFrame implements Transport
+func (t *websocketTransport) WriteFrame(ctx context.Context, data []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	return t.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// ReadFrame implements Transport
+func (t *websocketTransport) ReadFrame() ([]byte, error) {
+	_, data, err := t.conn.ReadMessage()
+	return data, err
+}
+
+// Close implements Transport
+func (t *websocketTransport) Close() error {
+	return t.conn.Close()
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// previewValueLength is how many characters of a string argument the approval preview shows
+const previewValueLength = 200
+
+// promptApproval asks on the terminal whether a tool call may proceed. Prompts
+// of concurrent calls are asked one at a time.
+func (h *CliHandler) promptApproval(ctx context.Context, request entity.ApprovalRequest) (bool, error) {
+	if !readline.IsTerminal(int(os.Stdin.Fd())) {
+		return false, errors.New("approval is required but stdin is not a terminal")
+	}
+
+	h.approvalMu.Lock()
+	defer h.approvalMu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	answers := h.stdinAnswers()
+	// Lines typed after an earlier prompt gave up answer nothing
+	for drained := false; !drained; {
+		select {
+		case _, ok := <-answers:
+			if !ok {
+				return false, errors.New("approval is required but stdin is closed")
+			}
+		default:
+			drained = true
+		}
+	}
+
+	writeApprovalPreview(h.stderr, request)
+	select {
+	case line, ok := <-answers:
+		if !ok {
+			fmt.Fprintln(h.stderr)
+			return false, errors.New("approval is required but stdin is closed")
+		}
+		return isYes(line), nil
+	case <-ctx.Done():
+		fmt.Fprintln(h.stderr)
+		return false, ctx.Err()
+	}
+}
+
+// stdinAnswers starts the one goroutine that reads stdin for approval prompts.
+// A read cannot be interrupted, so a prompt that times out leaves it waiting for
+// the next line instead of starting another reader.
+func (h *CliHandler) stdinAnswers() <-chan string {
+	h.answersOnce.Do(func() {
+		h.answers = make(chan string)
+		go func() {
+			defer close(h.answers)
+			reader := bufio.NewReader(os.Stdin)
+			for {
+				line, err := reader.ReadString('\n')
+				if line != "" {
+					h.answers <- line
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	})
+	return h.answers
+}
+
+// promptApproval asks in the shell whether a tool call may proceed. When ctx is
+// done first the pending read is interrupted, so the shell prompt that follows
+// does not race with it.
+func (s *shell) promptApproval(ctx context.Context, request entity.ApprovalRequest) (bool, error) {
+	if !s.interactive {
+		return false, errors.New("approval is required but the shell is not interactive")
+	}
+
+	type answer struct {
+		line string
+		err  error
+	}
+	writeApprovalPreview(s.out, request)
+	answers := make(chan answer, 1)
+	go func() {
+		line, err := s.in.ReadLine("")
+		answers <- answer{line, err}
+	}()
+
+	var a answer
+	select {
+	case a = <-answers:
+	case <-ctx.Done():
+		select {
+		case a = <-answers:
+		default:
+			s.in.Interrupt()
+			<-answers
+			return false, ctx.Err()
+		}
+	}
+	if errors.Is(a.err, readline.ErrInterrupt) || errors.Is(a.err, io.EOF) {
+		return false, nil
+	}
+	if a.err != nil {
+		return false, a.err
+	}
+	return isYes(a.line), nil
+}
+
+// writeApprovalPreview shows a tool call awaiting approval followed by the question
+func writeApprovalPreview(w io.Writer, request entity.ApprovalRequest) {
+	fmt.Fprintf(w, "Tool call needs approval: %s\n", request.Reason)
+	fmt.Fprintf(w, "  server: %s\n", request.Server)
+	fmt.Fprintf(w, "  tool:   %s\n", request.Tool)
+	if request.Caller != "" {
+		fmt.Fprintf(w, "  caller: %s\n", request.Caller)
+	}
+	if len(request.Arguments) > 0 {
+		data, err := json.MarshalIndent(previewValue(request.Arguments), "  ", "  ")
+		if err == nil {
+			fmt.Fprintf(w, "  arguments: %s\n", config.DefaultRedactor.Redact(string(data)))
+		}
+	}
+	fmt.Fprintf(w, "Approve? [y/N] (times out in %s) ", time.Until(request.ExpiresAt).Round(time.Second))
+}
+
+// previewValue shortens long strings of decoded JSON for the approval preview
+func previewValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		preview := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			preview[key] = previewValue(item)
+		}
+		return preview
+	case []interface{}:
+		preview := make([]interface{}, len(v))
+		for i, item := range v {
+			preview[i] = previewValue(item)
+		}
+		return preview
+	case string:
+		if runes := []rune(v); len(runes) > previewValueLength {
+			return fmt.Sprintf("%s... (%d more characters)", string(runes[:previewValueLength]), len(runes)-previewValueLength)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isYes reports whether an answer approves
+func isYes(answer string) bool {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// batchRequest is one line of a batch file. A line either names a protocol
+// method with its params, or a tool with its arguments.
+type batchRequest struct {
+	ID        json.RawMessage        `json:"id,omitempty"`
+	Method    string                 `json:"method,omitempty"`
+	Params    json.RawMessage        `json:"params,omitempty"`
+	Tool      string                 `json:"tool,omitempty"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// batchResult is one line of batch output
+type batchResult struct {
+	Line       int             `json:"line"`
+	ID         json.RawMessage `json:"id,omitempty"`
+	Method     string          `json:"method,omitempty"`
+	Tool       string          `json:"tool,omitempty"`
+	OK         bool            `json:"ok"`
+	Result     interface{}     `json:"result,omitempty"`
+	Error      *errorBody      `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"startedAt"`
+	DurationMs float64         `json:"durationMs"`
+}
+
+// batchLine is a non-empty input line waiting to be executed
+type batchLine struct {
+	number int
+	text   string
+}
+
+// runBatch executes the requests of a JSONL file and writes one result per line in input order
+func (h *CliHandler) runBatch(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("run", opts)
+	concurrency := fs.Int("concurrency", 1, "Number of requests executed in parallel")
+	timeout := fs.Duration("timeout", 0, "Timeout for each request (0 uses the client default)")
+
+	path, err := parseWithName(fs, args, "batch file (use - for stdin)")
+	if err != nil {
+		return err
+	}
+	if *concurrency < 1 {
+		return usageErrorf("run: --concurrency must be at least 1")
+	}
+
+	lines, err := readBatchLines(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.connect(ctx, opts); err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	// Stream results as soon as every earlier line has completed,
+	// unless the format needs the whole document at once
+	stream := !(opts.output == outputJSON || opts.output == outputYAML)
+	enc := json.NewEncoder(h.stdout)
+	results := make([]*batchResult, len(lines))
+	next := 0
+	var mu sync.Mutex
+	complete := func(i int, result *batchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = result
+		for stream && next < len(results) && results[next] != nil {
+			_ = enc.Encode(results[next])
+			next++
+		}
+	}
+
+	sem := make(chan struct{}, *concurrency)
+	var wg sync.WaitGroup
+	for i, line := range lines {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, line batchLine) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			complete(i, h.executeBatchLine(ctx, line, *timeout))
+		}(i, line)
+	}
+	wg.Wait()
+
+	if !stream {
+		if err := render(h.stdout, opts.output, view{document: map[string]interface{}{"results": results}}); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.OK {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d batch requests failed", failed, len(results))
+	}
+	return nil
+}
+
+// executeBatchLine parses and executes one line, never returning an error:
+// failures are recorded in the result
+func (h *CliHandler) executeBatchLine(ctx context.Context, line batchLine, timeout time.Duration) *batchResult {
+	result := &batchResult{
+		Line:      line.number,
+		StartedAt: time.Now().UTC(),
+	}
+
+	var req batchRequest
+	err := json.Unmarshal([]byte(line.text), &req)
+	if err == nil {
+		result.ID = req.ID
+		result.Method = req.Method
+		result.Tool = req.Tool
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		result.Result, err = h.executeBatchRequest(ctx, req)
+	} else {
+		err = fmt.Errorf("invalid JSON on line %d: %w", line.number, err)
+	}
+
+	result.DurationMs = float64(time.Since(result.StartedAt).Microseconds()) / 1000
+	if err != nil {
+		result.Error = newErrorBody(err)
+		return result
+	}
+	result.OK = true
+	return result
+}
+
+// executeBatchRequest executes a single batch request. Tool calls go through
+// ExecuteTool; any other method is sent as is and its raw result returned.
+func (h *CliHandler) executeBatchRequest(ctx context.Context, req batchRequest) (interface{}, error) {
+	var toolCall *entity.ToolCall
+	switch {
+	case req.Tool != "" && req.Method != "":
+		return nil, fmt.Errorf("a request must have either method or tool, not both")
+	case req.Tool != "":
+		toolCall = &entity.ToolCall{Name: req.Tool, Arguments: req.Arguments}
+	case req.Method == "tools/call":
+		toolCall = &entity.ToolCall{}
+		if err := json.Unmarshal(req.Params, toolCall); err != nil {
+			return nil, fmt.Errorf("invalid tools/call params: %w", err)
+		}
+	case req.Method == "":
+		return nil, fmt.Errorf("a request must have either method or tool")
+	default:
+		raw, err := h.mcpUsecase.SendRequest(ctx, req.Method, req.Params)
+		if err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+
+	result, err := h.mcpUsecase.ExecuteTool(ctx, *toolCall)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return result, &ExitError{Code: ExitToolError, Err: fmt.Errorf("tool %s returned an error", toolCall.Name)}
+	}
+	return result, nil
+}
+
+// readBatchLines reads the non-empty lines of a batch file; lines starting with # are comments
+func readBatchLines(path string) ([]batchLine, error) {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open batch file: %w", err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	var lines []batchLine
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		lines = append(lines, batchLine{number: number, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+	return lines, nil
+}
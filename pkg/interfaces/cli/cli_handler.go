@@ -2,19 +2,33 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/response"
+	httphandler "github.com/t-yamakoshi/go-mcp-client/pkg/interfaces/http"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/interfaces/message"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/tracing"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/usecase"
 )
 
 var _ IFCLIHandler = (*CliHandler)(nil)
 
+// logger is the logger of the cli component
+var logger = logging.Logger("cli")
+
+// tracingFlushTimeout bounds how long exporting the remaining spans may delay exit
+const tracingFlushTimeout = 5 * time.Second
+
 // IFCLIHandler defines the interface for command line interface operations
 type IFCLIHandler interface {
 	Run() error
@@ -24,74 +38,283 @@ type IFCLIHandler interface {
 type CliHandler struct {
 	mcpUsecase    usecase.IFMCPUsecase
 	configUsecase usecase.IFConfigUsecase
+	auditUsecase  *usecase.AuditUsecase
+	policyUsecase *usecase.PolicyUsecase
+	approvals     *usecase.ApprovalUsecase
+	serverPool    *usecase.ServerPool
 	msgHandler    message.IFMessageHandler
+	httpHandler   httphandler.IFHTTPHandler
+	stdout        io.Writer
+	stderr        io.Writer
+	// approvalMu keeps approval prompts of concurrent calls apart
+	approvalMu sync.Mutex
+	// answers receives the lines of stdin once the first approval prompt starts reading them
+	answers     chan string
+	answersOnce sync.Once
+	// shutdownTracing flushes the spans once tracing was set up by connect
+	shutdownTracing func(context.Context) error
+}
+
+// globalOptions holds the flags shared by every command
+type globalOptions struct {
+	configFile string
+	output     outputFormat
+	record     string
+	// overrides are configuration values given as flags
+	overrides []config.Override
+}
+
+// configFlags maps the global flags that override configuration values to their keys
+var configFlags = map[string]string{
+	"server":         "server_url",
+	"client-name":    "client_info.name",
+	"client-version": "client_info.version",
+	"log-level":      "log_level",
+	"log-format":     "log_format",
+	"log-frames":     "log_frames",
+	"otlp-endpoint":  "otlp_endpoint",
+	"use":            "default_server",
+	"profile":        "profile",
 }
 
+const usageText = `Usage: mcpclient [global flags] <command> [arguments]
+
+Commands:
+  tools list                                 List the tools offered by the server
+  tools call NAME [--arg k=v]... [--json {}] Call a tool and print its result
+  resources list                             List the resources offered by the server
+  resources read URI                         Print the contents of a resource
+  prompts list                               List the prompts offered by the server
+  prompts get NAME [--arg k=v]...            Render a prompt
+  ping                                       Check that the server responds
+  info                                       Show server information and capabilities
+  run FILE [--concurrency N] [--timeout D]    Execute the requests of a JSONL file (- for stdin)
+  config show [--resolved]                   Show the config file, or the effective configuration and its sources
+  config get PATH                            Print a value of the config file, e.g. mcpServers.github.url
+  config set PATH VALUE                      Set a value of the config file (saved atomically with a .bak backup)
+  config unset PATH                          Remove a value of the config file
+  config import FILE [--overwrite]           Import the mcpServers of another MCP client's config file
+  config schema                              Print the JSON Schema of the config file
+  audit verify [FILE...]                     Check the hash chain of the audit log and its rotations
+  gen go [--package P] [--out FILE]          Generate typed Go wrappers for the server's tools
+  serve [--addr A] [--allow-remote] [--watch] Serve the session as a REST gateway over HTTP, on 127.0.0.1:8080 by default
+  shell                                      Start an interactive shell over one session
+
+Global flags:
+`
+
 // NewCLIHandler creates a new CLI handler
-func NewCLIHandler(mcpUsecase *usecase.MCPUsecase, configUsecase *usecase.ConfigUsecase, msgHandler *message.MessageHandler) *CliHandler {
+func NewCLIHandler(mcpUsecase *usecase.MCPUsecase, configUsecase *usecase.ConfigUsecase, auditUsecase *usecase.AuditUsecase, policyUsecase *usecase.PolicyUsecase, approvals *usecase.ApprovalUsecase, serverPool *usecase.ServerPool, msgHandler *message.MessageHandler, httpHandler *httphandler.HTTPHandler) *CliHandler {
 	return &CliHandler{
 		mcpUsecase:    mcpUsecase,
 		configUsecase: configUsecase,
+		auditUsecase:  auditUsecase,
+		policyUsecase: policyUsecase,
+		approvals:     approvals,
+		serverPool:    serverPool,
 		msgHandler:    msgHandler,
+		httpHandler:   httpHandler,
+		stdout:        os.Stdout,
+		stderr:        os.Stderr,
 	}
 }
 
-// Run starts the CLI application
+// Run parses the command line, executes a single command and returns.
+// With a machine readable output format, errors are written to stderr as structured documents.
 func (h *CliHandler) Run() error {
-	// Parse command line flags
-	configFile := flag.String("config", "config.json", "Path to configuration file")
-	serverURL := flag.String("server", "", "MCP server URL (overrides config file)")
-	flag.Parse()
+	opts := &globalOptions{output: outputTable}
+	err := h.run(opts, os.Args[1:])
+	h.flushTracing()
+	if err == nil || errors.Is(err, flag.ErrHelp) || !opts.output.isMachine() {
+		return err
+	}
+
+	if renderErr := render(h.stderr, opts.output, errorView(err)); renderErr != nil {
+		return err
+	}
+	return &ExitError{Code: ExitCode(err), Err: err, Reported: true}
+}
+
+// run executes the command line without reporting errors
+func (h *CliHandler) run(opts *globalOptions, argv []string) error {
+	fs := flag.NewFlagSet("mcpclient", flag.ContinueOnError)
+	fs.SetOutput(h.stderr)
+	fs.StringVar(&opts.configFile, "config", "config.json", "Path to configuration file")
+	fs.String("server", "", "MCP server URL (overrides config file); replay://FILE replays a recording")
+	fs.String("client-name", "", "Client name sent to the server")
+	fs.String("client-version", "", "Client version sent to the server")
+	fs.String("log-level", "", "Log level: debug, info, warning or error")
+	fs.String("log-format", "", "Log format: text or json")
+	fs.Bool("log-frames", false, "Log every JSON-RPC frame sent and received at debug level")
+	fs.String("otlp-endpoint", "", "OTLP/HTTP endpoint to export traces to, e.g. http://localhost:4318")
+	fs.String("use", "", "Name of the mcpServers entry to connect to")
+	fs.String("profile", "", "Name of the profile to apply, e.g. dev, staging or prod")
+	var set setFlag
+	fs.Var(&set, "set", "Override a configuration value, e.g. -set request_timeout=1m (repeatable)")
+	fs.StringVar(&opts.record, "record", "", "Record all frames sent and received to a JSONL file")
+	fs.Var(&opts.output, "output", "Output format: table, json, jsonl, yaml or raw")
+	fs.Usage = func() {
+		fmt.Fprint(h.stderr, usageText)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(argv); err != nil {
+		return usageErrorf("%w", err)
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if key, ok := configFlags[f.Name]; ok {
+			opts.overrides = append(opts.overrides, config.Override{Key: key, Value: f.Value.String(), Flag: f.Name})
+		}
+	})
+	// -set comes last so that it wins over the named flags
+	opts.overrides = append(opts.overrides, set...)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return usageErrorf("no command given")
+	}
+
+	command, args := fs.Arg(0), fs.Args()[1:]
+
+	// The shell handles Ctrl+C per command instead of exiting
+	if command == "shell" {
+		return h.runShell(usecase.WithCaller(context.Background(), cliCaller()), opts, args)
+	}
+
+	// Cancel the running command on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ctx = usecase.WithCaller(ctx, cliCaller())
+	// Calls that need approval are asked for on the terminal; serve queues them instead
+	h.approvals.SetPrompter(h.promptApproval)
+
+	switch command {
+	case "tools":
+		return h.runTools(ctx, opts, args)
+	case "resources":
+		return h.runResources(ctx, opts, args)
+	case "prompts":
+		return h.runPrompts(ctx, opts, args)
+	case "ping":
+		return h.runPing(ctx, opts, args)
+	case "info":
+		return h.runInfo(ctx, opts, args)
+	case "run":
+		return h.runBatch(ctx, opts, args)
+	case "serve":
+		return h.runServe(ctx, opts, args)
+	case "gen":
+		return h.runGen(ctx, opts, args)
+	case "config":
+		return h.runConfig(ctx, opts, args)
+	case "audit":
+		return h.runAudit(ctx, opts, args)
+	case "help":
+		fs.Usage()
+		return nil
+	default:
+		return usageErrorf("unknown command %q", command)
+	}
+}
+
+// connect loads the configuration, connects to the server and initializes the protocol.
+// Callers must call disconnect when done.
+func (h *CliHandler) connect(ctx context.Context, opts *globalOptions) (*response.InitializeResponse, error) {
+	_, initResp, err := h.connectResolved(ctx, opts)
+	return initResp, err
+}
 
-	// Load configuration
-	config, err := h.configUsecase.LoadConfiguration(context.Background(), *configFile)
+// connectResolved is connect that also returns the configuration it connected with
+func (h *CliHandler) connectResolved(ctx context.Context, opts *globalOptions) (*config.Resolved, *response.InitializeResponse, error) {
+	resolved, err := h.configUsecase.ResolveConfiguration(ctx, opts.configFile, opts.overrides)
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
+	config := resolved.Config
 
-	// Override server URL if provided via command line
-	if *serverURL != "" {
-		config.ServerURL = *serverURL
+	if err := configureLogging(config); err != nil {
+		return nil, nil, usageErrorf("%w", err)
+	}
+	if h.shutdownTracing == nil {
+		shutdown, err := tracing.Configure(ctx, tracing.Options{Endpoint: config.OTLPEndpoint})
+		if err != nil {
+			return nil, nil, err
+		}
+		h.shutdownTracing = shutdown
+	}
+	if err := h.policyUsecase.SetPolicy(config.Policy); err != nil {
+		return nil, nil, usageErrorf("%w", err)
+	}
+	h.approvals.SetTimeout(config.ApprovalWait())
+	if err := h.auditUsecase.StartAudit(ctx, config.Audit); err != nil {
+		return nil, nil, err
 	}
 
-	// Set up context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	if opts.record != "" {
+		if err := h.mcpUsecase.StartRecording(ctx, opts.record); err != nil {
+			return nil, nil, err
+		}
+	}
 
-	// Handle graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	h.mcpUsecase.SetTimeouts(config.Timeouts())
 
-	go func() {
-		<-sigChan
-		log.Println("Received shutdown signal, closing connection...")
-		cancel()
-	}()
+	name, server, err := resolved.ActiveServer()
+	if err != nil {
+		h.stopRecording()
+		return nil, nil, usageErrorf("%w", err)
+	}
 
-	// Establish connection
-	log.Printf("Connecting to MCP server at %s", config.ServerURL)
-	if err := h.mcpUsecase.EstablishConnection(ctx, config.ServerURL); err != nil {
-		return fmt.Errorf("failed to connect to MCP server: %w", err)
+	if err := h.mcpUsecase.EstablishServerConnection(ctx, name, server); err != nil {
+		h.stopRecording()
+		return nil, nil, connectionError(fmt.Errorf("failed to connect to MCP server: %w", err))
 	}
-	defer h.mcpUsecase.CloseConnection(ctx)
 
-	// Initialize the protocol
-	log.Println("Initializing MCP protocol...")
 	initResp, err := h.mcpUsecase.InitializeProtocol(ctx, config.ClientInfo)
 	if err != nil {
-		return fmt.Errorf("failed to initialize MCP protocol: %w", err)
+		h.disconnect()
+		return nil, nil, connectionError(fmt.Errorf("failed to initialize MCP protocol: %w", err))
 	}
 
-	log.Printf("Successfully connected to MCP server: %s v%s",
-		initResp.ServerInfo.Name, initResp.ServerInfo.Version)
-
 	// Register message handlers
 	h.msgHandler.RegisterHandlers(&h.mcpUsecase)
 
-	// Keep the connection alive
-	log.Println("MCP client is running. Press Ctrl+C to exit.")
-	<-ctx.Done()
-	log.Println("MCP client shutting down...")
+	return resolved, initResp, nil
+}
+
+// disconnect closes the connection opened by connect
+func (h *CliHandler) disconnect() {
+	if err := h.mcpUsecase.CloseConnection(context.Background()); err != nil {
+		logger.Warn("failed to close connection", "error", err)
+	}
+	h.stopRecording()
+	if err := h.auditUsecase.StopAudit(context.Background()); err != nil {
+		logger.Warn("failed to close the audit log", "error", err)
+	}
+}
+
+// stopRecording finishes a recording started by connect
+func (h *CliHandler) stopRecording() {
+	if err := h.mcpUsecase.StopRecording(context.Background()); err != nil {
+		logger.Warn("failed to stop recording", "error", err)
+	}
+}
+
+// flushTracing exports the pending spans before the process exits
+func (h *CliHandler) flushTracing() {
+	if h.shutdownTracing == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancel()
+	if err := h.shutdownTracing(ctx); err != nil {
+		logger.Warn("failed to export traces", "error", err)
+	}
+}
 
-	return nil
+// configureLogging applies the log settings of a resolved configuration
+func configureLogging(cfg *config.Config) error {
+	return logging.Configure(logging.Options{
+		Level:  cfg.LogLevel,
+		Format: cfg.LogFormat,
+		Frames: cfg.LogFrames,
+	})
 }
@@ -0,0 +1,407 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/interfaces/codegen"
+	httphandler "github.com/t-yamakoshi/go-mcp-client/pkg/interfaces/http"
+)
+
+// runTools dispatches the tools subcommands
+func (h *CliHandler) runTools(ctx context.Context, opts *globalOptions, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("tools: missing subcommand (list, call)")
+	}
+
+	switch args[0] {
+	case "list":
+		return h.runToolsList(ctx, opts, args[1:])
+	case "call":
+		return h.runToolsCall(ctx, opts, args[1:])
+	default:
+		return usageErrorf("tools: unknown subcommand %q", args[0])
+	}
+}
+
+// runResources dispatches the resources subcommands
+func (h *CliHandler) runResources(ctx context.Context, opts *globalOptions, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("resources: missing subcommand (list, read)")
+	}
+
+	switch args[0] {
+	case "list":
+		return h.runResourcesList(ctx, opts, args[1:])
+	case "read":
+		return h.runResourcesRead(ctx, opts, args[1:])
+	default:
+		return usageErrorf("resources: unknown subcommand %q", args[0])
+	}
+}
+
+// runPrompts dispatches the prompts subcommands
+func (h *CliHandler) runPrompts(ctx context.Context, opts *globalOptions, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("prompts: missing subcommand (list, get)")
+	}
+
+	switch args[0] {
+	case "list":
+		return h.runPromptsList(ctx, opts, args[1:])
+	case "get":
+		return h.runPromptsGet(ctx, opts, args[1:])
+	default:
+		return usageErrorf("prompts: unknown subcommand %q", args[0])
+	}
+}
+
+// runToolsList prints the tools offered by the server
+func (h *CliHandler) runToolsList(ctx context.Context, opts *globalOptions, args []string) error {
+	if err := parseNoArgs(newFlagSet("tools list", opts), args); err != nil {
+		return err
+	}
+
+	if _, err := h.connect(ctx, opts); err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	tools, err := h.mcpUsecase.GetAvailableTools(ctx)
+	if err != nil {
+		return err
+	}
+
+	return render(h.stdout, opts.output, toolsView(tools))
+}
+
+// runToolsCall calls a tool and prints its result
+func (h *CliHandler) runToolsCall(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("tools call", opts)
+	toolArgs := keyValueFlag{}
+	fs.Var(toolArgs, "arg", "Tool argument as key=value (repeatable)")
+	jsonArgs := fs.String("json", "", "Tool arguments as a JSON object")
+
+	name, err := parseWithName(fs, args, "tool name")
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.connect(ctx, opts); err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	// Look up the input schema so that --arg values get the right types
+	var inputSchema map[string]interface{}
+	if len(toolArgs) > 0 {
+		tools, err := h.mcpUsecase.GetAvailableTools(ctx)
+		if err != nil {
+			return err
+		}
+		for _, tool := range tools {
+			if tool.Name == name {
+				inputSchema = tool.InputSchema
+				break
+			}
+		}
+	}
+
+	arguments, err := buildArguments(*jsonArgs, toolArgs, inputSchema)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.mcpUsecase.ExecuteTool(ctx, entity.ToolCall{
+		Name:      name,
+		Arguments: arguments,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := render(h.stdout, opts.output, toolResultView(result)); err != nil {
+		return err
+	}
+	if result.IsError {
+		return &ExitError{Code: ExitToolError, Err: fmt.Errorf("tool %s returned an error", name)}
+	}
+	return nil
+}
+
+// runResourcesList prints the resources offered by the server
+func (h *CliHandler) runResourcesList(ctx context.Context, opts *globalOptions, args []string) error {
+	if err := parseNoArgs(newFlagSet("resources list", opts), args); err != nil {
+		return err
+	}
+
+	if _, err := h.connect(ctx, opts); err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	resources, err := h.mcpUsecase.GetAvailableResources(ctx)
+	if err != nil {
+		return err
+	}
+
+	return render(h.stdout, opts.output, resourcesView(resources))
+}
+
+// runResourcesRead prints the contents of a resource
+func (h *CliHandler) runResourcesRead(ctx context.Context, opts *globalOptions, args []string) error {
+	uri, err := parseWithName(newFlagSet("resources read", opts), args, "resource URI")
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.connect(ctx, opts); err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	contents, err := h.mcpUsecase.ReadResource(ctx, uri)
+	if err != nil {
+		return err
+	}
+
+	return render(h.stdout, opts.output, contentsView(contents))
+}
+
+// runPromptsList prints the prompts offered by the server
+func (h *CliHandler) runPromptsList(ctx context.Context, opts *globalOptions, args []string) error {
+	if err := parseNoArgs(newFlagSet("prompts list", opts), args); err != nil {
+		return err
+	}
+
+	if _, err := h.connect(ctx, opts); err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	prompts, err := h.mcpUsecase.GetAvailablePrompts(ctx)
+	if err != nil {
+		return err
+	}
+
+	return render(h.stdout, opts.output, promptsView(prompts))
+}
+
+// runPromptsGet renders a prompt and prints its messages
+func (h *CliHandler) runPromptsGet(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("prompts get", opts)
+	promptArgs := keyValueFlag{}
+	fs.Var(promptArgs, "arg", "Prompt argument as key=value (repeatable)")
+
+	name, err := parseWithName(fs, args, "prompt name")
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.connect(ctx, opts); err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	result, err := h.mcpUsecase.GetPrompt(ctx, entity.PromptRequest{
+		Name:      name,
+		Arguments: promptArgs,
+	})
+	if err != nil {
+		return err
+	}
+
+	return render(h.stdout, opts.output, promptResultView(result))
+}
+
+// runPing checks that the server responds and prints the round trip time
+func (h *CliHandler) runPing(ctx context.Context, opts *globalOptions, args []string) error {
+	if err := parseNoArgs(newFlagSet("ping", opts), args); err != nil {
+		return err
+	}
+
+	initResp, err := h.connect(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	start := time.Now()
+	if err := h.mcpUsecase.Ping(ctx); err != nil {
+		return err
+	}
+
+	return render(h.stdout, opts.output, pingView(initResp.ServerInfo.Name, time.Since(start)))
+}
+
+// runInfo prints server information and capabilities
+func (h *CliHandler) runInfo(ctx context.Context, opts *globalOptions, args []string) error {
+	if err := parseNoArgs(newFlagSet("info", opts), args); err != nil {
+		return err
+	}
+
+	initResp, err := h.connect(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	return render(h.stdout, opts.output, serverInfoView(initResp))
+}
+
+// runServe exposes the MCP session as a REST gateway until interrupted
+func (h *CliHandler) runServe(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("serve", opts)
+	addr := fs.String("addr", "127.0.0.1:8080", "Address for the HTTP server to listen on")
+	allowRemote := fs.Bool("allow-remote", false, "Allow an --addr that accepts connections from other hosts")
+	watch := fs.Bool("watch", false, "Reload the config file on change and connect, disconnect or reconnect servers to match")
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+	// Anyone who can reach the gateway can call tools, so listening beyond this host is opt-in
+	if !*allowRemote && !httphandler.IsLoopbackAddr(*addr) {
+		return usageErrorf("serve: --addr %s accepts connections from other hosts; pass --allow-remote to listen on it", *addr)
+	}
+
+	// Calls that need approval wait for the approve and deny endpoints
+	h.approvals.SetPrompter(nil)
+
+	resolved, _, err := h.connectResolved(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer h.disconnect()
+	if err := h.httpHandler.SetAuth(resolved.Config.HTTP); err != nil {
+		return err
+	}
+
+	// The other configured servers are served under /servers/{name}
+	defer h.serverPool.Close(context.Background())
+	if err := h.serverPool.Start(ctx, resolved); err != nil {
+		logger.Warn("some servers could not be connected", "error", err)
+	}
+	if *watch {
+		if err := h.watchServers(ctx, opts, resolved); err != nil {
+			return err
+		}
+	}
+
+	return h.httpHandler.StartServer(ctx, *addr)
+}
+
+// watchServers keeps the sessions of the server pool, and the primary session,
+// in line with the config file until ctx is done
+func (h *CliHandler) watchServers(ctx context.Context, opts *globalOptions, resolved *config.Resolved) error {
+	audit := resolved.Config.Audit
+	err := h.configUsecase.WatchConfiguration(ctx, opts.configFile, opts.overrides, func(resolved *config.Resolved) {
+		if err := configureLogging(resolved.Config); err != nil {
+			logger.Warn("failed to apply log settings", "error", err)
+		}
+		if err := h.policyUsecase.SetPolicy(resolved.Config.Policy); err != nil {
+			logger.Warn("failed to apply the tool policy", "error", err)
+		}
+		h.approvals.SetTimeout(resolved.Config.ApprovalWait())
+		if err := h.httpHandler.SetAuth(resolved.Config.HTTP); err != nil {
+			logger.Warn("failed to apply the http settings", "error", err)
+		}
+		if !reflect.DeepEqual(resolved.Config.Audit, audit) {
+			if err := h.auditUsecase.StartAudit(ctx, resolved.Config.Audit); err != nil {
+				logger.Warn("failed to apply the audit settings", "error", err)
+			} else {
+				audit = resolved.Config.Audit
+				logger.Info("audit settings reloaded", "path", audit.Path)
+			}
+		}
+		if err := h.serverPool.Apply(ctx, resolved); err != nil {
+			logger.Warn("some servers could not be connected", "error", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", opts.configFile, err)
+	}
+	logger.Info("watching configuration for changes", "path", opts.configFile)
+	return nil
+}
+
+// runGen dispatches the gen subcommands
+func (h *CliHandler) runGen(ctx context.Context, opts *globalOptions, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("gen: missing language (go)")
+	}
+
+	switch args[0] {
+	case "go":
+		return h.runGenGo(ctx, opts, args[1:])
+	default:
+		return usageErrorf("gen: unsupported language %q", args[0])
+	}
+}
+
+// runGenGo writes a Go package with typed wrappers for the tools of the server
+func (h *CliHandler) runGenGo(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("gen go", opts)
+	pkg := fs.String("package", "mcptools", "Name of the generated package")
+	out := fs.String("out", "-", "File to write the generated code to (- for stdout)")
+	only := fs.String("tools", "", "Comma separated tool names to generate (default all)")
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+
+	initResp, err := h.connect(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	tools, err := h.mcpUsecase.GetAvailableTools(ctx)
+	if err != nil {
+		return err
+	}
+	if *only != "" {
+		if tools, err = selectTools(tools, strings.Split(*only, ",")); err != nil {
+			return err
+		}
+	}
+
+	src, err := codegen.GenerateGo(codegen.GoOptions{
+		Package: *pkg,
+		Server:  fmt.Sprintf("%s %s", initResp.ServerInfo.Name, initResp.ServerInfo.Version),
+	}, tools)
+	if err != nil {
+		return usageErrorf("gen go: %w", err)
+	}
+
+	if *out == "-" {
+		_, err = h.stdout.Write(src)
+		return err
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *out, err)
+	}
+	fmt.Fprintf(h.stderr, "Generated %d tool wrappers in %s\n", len(tools), *out)
+	return nil
+}
+
+// selectTools returns the named tools in the given order
+func selectTools(tools []entity.Tool, names []string) ([]entity.Tool, error) {
+	byName := make(map[string]entity.Tool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+
+	selected := make([]entity.Tool, 0, len(names))
+	for _, name := range names {
+		tool, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("server has no tool named %q", name)
+		}
+		selected = append(selected, tool)
+	}
+	return selected, nil
+}
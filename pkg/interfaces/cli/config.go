@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+)
+
+// runConfig dispatches the config subcommands
+func (h *CliHandler) runConfig(ctx context.Context, opts *globalOptions, args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("config: missing subcommand (show, get, set, unset, import, schema)")
+	}
+
+	switch args[0] {
+	case "show":
+		return h.runConfigShow(ctx, opts, args[1:])
+	case "get":
+		return h.runConfigGet(ctx, opts, args[1:])
+	case "set":
+		return h.runConfigSet(ctx, opts, args[1:])
+	case "unset":
+		return h.runConfigUnset(ctx, opts, args[1:])
+	case "import":
+		return h.runConfigImport(ctx, opts, args[1:])
+	case "schema":
+		return h.runConfigSchema(ctx, opts, args[1:])
+	default:
+		return usageErrorf("config: unknown subcommand %q", args[0])
+	}
+}
+
+// runConfigShow prints the configuration file, or with --resolved the effective
+// configuration after all layers are applied and the source of each value
+func (h *CliHandler) runConfigShow(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("config show", opts)
+	resolvedFlag := fs.Bool("resolved", false, "Show the effective configuration and where each value came from")
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+
+	if *resolvedFlag {
+		resolved, err := h.configUsecase.ResolveConfiguration(ctx, opts.configFile, opts.overrides)
+		if err != nil {
+			return err
+		}
+		resolved.Config = config.DefaultRedactor.RedactConfig(resolved.Config)
+		return render(h.stdout, opts.output, resolvedConfigView(resolved))
+	}
+
+	cfg, err := h.configUsecase.LoadConfiguration(ctx, opts.configFile)
+	if err != nil {
+		return err
+	}
+	return render(h.stdout, opts.output, configView(cfg))
+}
+
+// runConfigGet prints the value at a path of the config file
+func (h *CliHandler) runConfigGet(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("config get", opts)
+	if err := fs.Parse(args); err != nil {
+		return usageErrorf("%w", err)
+	}
+	if fs.NArg() != 1 {
+		return usageErrorf("config get: expected PATH")
+	}
+
+	cfg, err := h.configUsecase.LoadConfiguration(ctx, opts.configFile)
+	if err != nil {
+		return err
+	}
+	value, err := h.configUsecase.GetConfigurationValue(ctx, cfg, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return render(h.stdout, opts.output, valueView(value))
+}
+
+// runConfigSet sets the value at a path of the config file
+func (h *CliHandler) runConfigSet(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("config set", opts)
+	if err := fs.Parse(args); err != nil {
+		return usageErrorf("%w", err)
+	}
+	if fs.NArg() != 2 {
+		return usageErrorf("config set: expected PATH VALUE")
+	}
+	return h.updateConfigFile(ctx, opts, config.Update{Path: fs.Arg(0), Value: fs.Arg(1)})
+}
+
+// runConfigUnset removes the value at a path of the config file
+func (h *CliHandler) runConfigUnset(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("config unset", opts)
+	if err := fs.Parse(args); err != nil {
+		return usageErrorf("%w", err)
+	}
+	if fs.NArg() != 1 {
+		return usageErrorf("config unset: expected PATH")
+	}
+	return h.updateConfigFile(ctx, opts, config.Update{Path: fs.Arg(0), Unset: true})
+}
+
+// updateConfigFile applies an update to the config file and saves it
+func (h *CliHandler) updateConfigFile(ctx context.Context, opts *globalOptions, update config.Update) error {
+	cfg, err := h.configUsecase.LoadConfiguration(ctx, opts.configFile)
+	if err != nil {
+		return err
+	}
+	if err := h.configUsecase.UpdateConfiguration(ctx, cfg, update); err != nil {
+		return usageErrorf("%w", err)
+	}
+	if err := h.configUsecase.SaveConfiguration(ctx, cfg, opts.configFile); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(h.stderr, "Updated %s (previous version in %s.bak)\n", opts.configFile, opts.configFile)
+	return nil
+}
+
+// valueView renders a single configuration value; objects and arrays are printed as JSON
+func valueView(value interface{}) view {
+	return view{
+		document: value,
+		table: func(w io.Writer) error {
+			switch v := value.(type) {
+			case map[string]interface{}, []interface{}:
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return enc.Encode(v)
+			default:
+				_, err := fmt.Fprintln(w, v)
+				return err
+			}
+		},
+	}
+}
+
+// runConfigImport adds the servers of another MCP client's configuration file,
+// such as claude_desktop_config.json or .vscode/mcp.json, to the config file
+func (h *CliHandler) runConfigImport(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("config import", opts)
+	overwrite := fs.Bool("overwrite", false, "Replace servers that are already defined")
+	if err := fs.Parse(args); err != nil {
+		return usageErrorf("%w", err)
+	}
+	if fs.NArg() != 1 {
+		return usageErrorf("config import: expected FILE")
+	}
+
+	cfg, err := h.configUsecase.LoadConfiguration(ctx, opts.configFile)
+	if err != nil {
+		return err
+	}
+
+	added, err := h.configUsecase.ImportServers(ctx, cfg, fs.Arg(0), *overwrite)
+	if err != nil {
+		return err
+	}
+	if err := h.configUsecase.SaveConfiguration(ctx, cfg, opts.configFile); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(h.stderr, "Imported %d server(s) into %s\n", len(added), opts.configFile)
+	return render(h.stdout, opts.output, serversView(cfg))
+}
+
+// runConfigSchema prints the JSON Schema of the config file, for editors and CI checks
+func (h *CliHandler) runConfigSchema(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("config schema", opts)
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(config.Schema(), &document); err != nil {
+		return fmt.Errorf("invalid embedded schema: %w", err)
+	}
+	return render(h.stdout, opts.output, view{
+		document: document,
+		table: func(w io.Writer) error {
+			_, err := w.Write(config.Schema())
+			return err
+		},
+	})
+}
+
+// configView renders a configuration as key and value pairs
+func configView(cfg *config.Config) view {
+	return view{
+		document: cfg,
+		table: func(w io.Writer) error {
+			return writeConfig(w, cfg, nil)
+		},
+		raw: func(w io.Writer) error {
+			return writeConfigAssignments(w, cfg)
+		},
+	}
+}
+
+// resolvedConfigView renders an effective configuration with the source of each value
+func resolvedConfigView(resolved *config.Resolved) view {
+	return view{
+		document: resolved,
+		table: func(w io.Writer) error {
+			return writeConfig(w, resolved.Config, resolved.Sources)
+		},
+		raw: func(w io.Writer) error {
+			return writeConfigAssignments(w, resolved.Config)
+		},
+	}
+}
+
+// writeConfig prints configuration values as a table, with a source column when sources are given
+func writeConfig(out io.Writer, cfg *config.Config, sources map[string]config.Source) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if sources != nil {
+		fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	} else {
+		fmt.Fprintln(w, "KEY\tVALUE")
+	}
+
+	for _, key := range append(config.Keys(), config.CompositeKeys()...) {
+		value, _ := cfg.Get(key)
+		if sources == nil {
+			fmt.Fprintf(w, "%s\t%s\n", key, value)
+			continue
+		}
+		source, ok := sources[key]
+		if !ok {
+			source = config.Source{Layer: "unset"}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", key, value, source)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(cfg.MCPServers) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	return writeServers(out, cfg)
+}
+
+// serversView renders the mcpServers entries of a configuration
+func serversView(cfg *config.Config) view {
+	return view{
+		document: struct {
+			MCPServers map[string]config.ServerConfig `json:"mcpServers"`
+		}{cfg.MCPServers},
+		table: func(w io.Writer) error {
+			return writeServers(w, cfg)
+		},
+		raw: func(w io.Writer) error {
+			for _, name := range cfg.ServerNames() {
+				fmt.Fprintln(w, name)
+			}
+			return nil
+		},
+	}
+}
+
+// writeServers prints the mcpServers entries as a table; the default server is marked with *
+func writeServers(out io.Writer, cfg *config.Config) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVER\tTRANSPORT\tADDRESS\tSTATUS")
+	for _, name := range cfg.ServerNames() {
+		server := cfg.MCPServers[name]
+		status := "enabled"
+		if server.Disabled {
+			status = "disabled"
+		}
+		if name == cfg.DefaultServer {
+			name += " *"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, server.TransportType(), server.Address(), status)
+	}
+	return w.Flush()
+}
+
+// writeConfigAssignments prints configuration values as environment variable assignments
+func writeConfigAssignments(out io.Writer, cfg *config.Config) error {
+	for _, key := range config.Keys() {
+		value, _ := cfg.Get(key)
+		if _, err := fmt.Fprintf(out, "%s=%s\n", config.EnvVar(key), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
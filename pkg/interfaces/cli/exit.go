@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// Exit codes returned by the CLI
+const (
+	ExitOK         = 0
+	ExitFailure    = 1
+	ExitUsage      = 2
+	ExitConnection = 3
+	ExitToolError  = 4
+)
+
+// ExitError wraps an error with the process exit code it should produce
+type ExitError struct {
+	Code int
+	Err  error
+	// Reported is set once the error has been written to stderr
+	Reported bool
+}
+
+// Error implements the error interface
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for an error returned by Run
+func ExitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitFailure
+}
+
+// Reported reports whether Run already wrote the error to stderr
+func Reported(err error) bool {
+	var exitErr *ExitError
+	return errors.As(err, &exitErr) && exitErr.Reported
+}
+
+// usageErrorf returns an error that exits with ExitUsage
+func usageErrorf(format string, args ...interface{}) error {
+	return &ExitError{Code: ExitUsage, Err: fmt.Errorf(format, args...)}
+}
+
+// connectionError wraps an error so that it exits with ExitConnection
+func connectionError(err error) error {
+	return &ExitError{Code: ExitConnection, Err: err}
+}
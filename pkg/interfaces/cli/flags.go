@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+)
+
+// keyValueFlag collects repeated key=value flags
+type keyValueFlag map[string]string
+
+// String implements flag.Value
+func (f keyValueFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value
+func (f keyValueFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	f[key] = val
+	return nil
+}
+
+// setFlag collects repeated -set key=value flags that override any configuration value
+type setFlag []config.Override
+
+// String implements flag.Value
+func (f *setFlag) String() string {
+	pairs := make([]string, 0, len(*f))
+	for _, override := range *f {
+		pairs = append(pairs, override.Key+"="+override.Value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value
+func (f *setFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	if !slices.Contains(config.Keys(), key) {
+		return fmt.Errorf("unknown configuration key %q (see config show --resolved)", key)
+	}
+	*f = append(*f, config.Override{Key: key, Value: val, Flag: "set " + key})
+	return nil
+}
+
+// newFlagSet creates a flag set for a subcommand.
+// Global flags that only affect output may also be given after the subcommand.
+func newFlagSet(name string, opts *globalOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Var(&opts.output, "output", "Output format: table, json, jsonl, yaml or raw")
+	return fs
+}
+
+// parseWithName parses subcommand flags around a single positional argument,
+// which may appear before or after the flags
+func parseWithName(fs *flag.FlagSet, args []string, what string) (string, error) {
+	name := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return "", usageErrorf("%s: %w", fs.Name(), err)
+	}
+
+	rest := fs.Args()
+	if name == "" && len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+	if name == "" {
+		return "", usageErrorf("%s: missing %s", fs.Name(), what)
+	}
+	if len(rest) > 0 {
+		return "", usageErrorf("%s: unexpected arguments: %s", fs.Name(), strings.Join(rest, " "))
+	}
+	return name, nil
+}
+
+// buildArguments merges --json and --arg values into tool arguments.
+// --arg values are coerced using the tool's input schema when available.
+func buildArguments(jsonArgs string, args keyValueFlag, inputSchema map[string]interface{}) (map[string]interface{}, error) {
+	arguments := make(map[string]interface{})
+	if jsonArgs != "" {
+		if err := json.Unmarshal([]byte(jsonArgs), &arguments); err != nil {
+			return nil, usageErrorf("invalid --json arguments: %v", err)
+		}
+	}
+
+	properties, _ := inputSchema["properties"].(map[string]interface{})
+	for key, raw := range args {
+		schema, _ := properties[key].(map[string]interface{})
+		value, err := coerceArgument(raw, schema)
+		if err != nil {
+			return nil, usageErrorf("invalid value for argument %s: %v", key, err)
+		}
+		arguments[key] = value
+	}
+	return arguments, nil
+}
+
+// coerceArgument converts a raw command line value to the type declared in schema
+func coerceArgument(raw string, schema map[string]interface{}) (interface{}, error) {
+	schemaType, _ := schema["type"].(string)
+	switch schemaType {
+	case "string":
+		return raw, nil
+	case "integer":
+		return strconv.ParseInt(raw, 10, 64)
+	case "number":
+		return strconv.ParseFloat(raw, 64)
+	case "boolean":
+		return strconv.ParseBool(raw)
+	case "object", "array":
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	default:
+		// Without a schema, accept JSON literals and fall back to a plain string
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value, nil
+		}
+		return raw, nil
+	}
+}
+
+// parseNoArgs parses subcommand flags that take no positional arguments
+func parseNoArgs(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return usageErrorf("%s: %w", fs.Name(), err)
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("%s: unexpected arguments: %s", fs.Name(), strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
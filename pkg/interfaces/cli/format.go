@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/response"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat selects how command results are written
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+	outputJSONL outputFormat = "jsonl"
+	outputYAML  outputFormat = "yaml"
+	outputRaw   outputFormat = "raw"
+)
+
+// String implements flag.Value
+func (f *outputFormat) String() string {
+	return string(*f)
+}
+
+// Set implements flag.Value
+func (f *outputFormat) Set(value string) error {
+	switch outputFormat(value) {
+	case outputTable, outputJSON, outputJSONL, outputYAML, outputRaw:
+		*f = outputFormat(value)
+		return nil
+	default:
+		return fmt.Errorf("must be one of table, json, jsonl, yaml, raw")
+	}
+}
+
+// isMachine reports whether the format is meant to be parsed by programs
+func (f outputFormat) isMachine() bool {
+	return f == outputJSON || f == outputJSONL || f == outputYAML
+}
+
+// view is a command result that can be rendered in every output format
+type view struct {
+	// document is the stable shape written by json and yaml
+	document interface{}
+	// items are written one per line by jsonl; document is used when nil
+	items []interface{}
+	table func(io.Writer) error
+	raw   func(io.Writer) error
+}
+
+// render writes a view in the given format
+func render(out io.Writer, format outputFormat, v view) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v.document)
+	case outputJSONL:
+		enc := json.NewEncoder(out)
+		if v.items == nil {
+			return enc.Encode(v.document)
+		}
+		for _, item := range v.items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case outputYAML:
+		return writeYAML(out, v.document)
+	case outputRaw:
+		if v.raw != nil {
+			return v.raw(out)
+		}
+		return v.table(out)
+	default:
+		return v.table(out)
+	}
+}
+
+// writeYAML writes a document as YAML with the same keys as its JSON form
+func writeYAML(out io.Writer, document interface{}) error {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	// JSON is valid YAML; decoding it into a node keeps the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to convert output to YAML: %w", err)
+	}
+	resetStyle(&node)
+
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// resetStyle switches a node tree decoded from JSON to block style
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// Stable JSON documents written by the machine readable formats
+type (
+	toolsDocument struct {
+		Tools []entity.Tool `json:"tools"`
+	}
+	resourcesDocument struct {
+		Resources []entity.Resource `json:"resources"`
+	}
+	contentsDocument struct {
+		Contents []entity.ResourceContents `json:"contents"`
+	}
+	promptsDocument struct {
+		Prompts []entity.Prompt `json:"prompts"`
+	}
+	pingDocument struct {
+		Server    string  `json:"server"`
+		LatencyMs float64 `json:"latencyMs"`
+	}
+	errorDocument struct {
+		Error errorBody `json:"error"`
+	}
+	errorBody struct {
+		Type     string      `json:"type"`
+		Message  string      `json:"message"`
+		ExitCode int         `json:"exitCode"`
+		Code     int         `json:"code,omitempty"`
+		Data     interface{} `json:"data,omitempty"`
+	}
+)
+
+// toolsView renders a tool list
+func toolsView(tools []entity.Tool) view {
+	return view{
+		document: toolsDocument{Tools: tools},
+		items:    toItems(tools),
+		table:    func(w io.Writer) error { return writeTools(w, tools) },
+		raw: func(w io.Writer) error {
+			for _, tool := range tools {
+				fmt.Fprintln(w, tool.Name)
+			}
+			return nil
+		},
+	}
+}
+
+// toolDetailsView renders a single tool definition
+func toolDetailsView(tool entity.Tool) view {
+	return view{
+		document: tool,
+		table:    func(w io.Writer) error { return writeToolDetails(w, tool) },
+		raw: func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(tool.InputSchema)
+		},
+	}
+}
+
+// toolResultView renders the result of a tool call
+func toolResultView(result *entity.ToolResult) view {
+	return view{
+		document: result,
+		table: func(w io.Writer) error {
+			writeToolResult(w, result)
+			return nil
+		},
+		raw: func(w io.Writer) error {
+			for _, content := range result.Content {
+				writeRawContent(w, content)
+			}
+			return nil
+		},
+	}
+}
+
+// resourcesView renders a resource list
+func resourcesView(resources []entity.Resource) view {
+	return view{
+		document: resourcesDocument{Resources: resources},
+		items:    toItems(resources),
+		table:    func(w io.Writer) error { return writeResources(w, resources) },
+		raw: func(w io.Writer) error {
+			for _, resource := range resources {
+				fmt.Fprintln(w, resource.URI)
+			}
+			return nil
+		},
+	}
+}
+
+// contentsView renders the contents of a resource
+func contentsView(contents []entity.ResourceContents) view {
+	return view{
+		document: contentsDocument{Contents: contents},
+		items:    toItems(contents),
+		table: func(w io.Writer) error {
+			for _, content := range contents {
+				if err := writeResourceContents(w, content); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// promptsView renders a prompt list
+func promptsView(prompts []entity.Prompt) view {
+	return view{
+		document: promptsDocument{Prompts: prompts},
+		items:    toItems(prompts),
+		table:    func(w io.Writer) error { return writePrompts(w, prompts) },
+		raw: func(w io.Writer) error {
+			for _, prompt := range prompts {
+				fmt.Fprintln(w, prompt.Name)
+			}
+			return nil
+		},
+	}
+}
+
+// promptResultView renders a rendered prompt
+func promptResultView(result *entity.PromptResult) view {
+	return view{
+		document: result,
+		items:    toItems(result.Messages),
+		table: func(w io.Writer) error {
+			writePromptResult(w, result)
+			return nil
+		},
+		raw: func(w io.Writer) error {
+			for _, msg := range result.Messages {
+				writeRawContent(w, msg.Content)
+			}
+			return nil
+		},
+	}
+}
+
+// serverInfoView renders the initialize response
+func serverInfoView(initResp *response.InitializeResponse) view {
+	return view{
+		document: initResp,
+		table:    func(w io.Writer) error { return writeServerInfo(w, initResp) },
+		raw: func(w io.Writer) error {
+			_, err := fmt.Fprintf(w, "%s %s\n", initResp.ServerInfo.Name, initResp.ServerInfo.Version)
+			return err
+		},
+	}
+}
+
+// pingView renders a ping round trip
+func pingView(server string, latency time.Duration) view {
+	doc := pingDocument{
+		Server:    server,
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+	}
+	return view{
+		document: doc,
+		table: func(w io.Writer) error {
+			_, err := fmt.Fprintf(w, "pong from %s in %s\n", server, latency.Round(time.Microsecond))
+			return err
+		},
+		raw: func(w io.Writer) error {
+			_, err := fmt.Fprintf(w, "%g\n", doc.LatencyMs)
+			return err
+		},
+	}
+}
+
+// errorView renders a command failure
+func errorView(err error) view {
+	body := newErrorBody(err)
+	return view{
+		document: errorDocument{Error: *body},
+		table: func(w io.Writer) error {
+			_, err := fmt.Fprintf(w, "error: %s\n", body.Message)
+			return err
+		},
+	}
+}
+
+// newErrorBody builds the stable error shape, including MCP error details when present
+func newErrorBody(err error) *errorBody {
+	body := &errorBody{
+		Type:     errorType(ExitCode(err)),
+		Message:  err.Error(),
+		ExitCode: ExitCode(err),
+	}
+
+	var mcpErr *entity.Error
+	if errors.As(err, &mcpErr) {
+		body.Code = mcpErr.Code
+		body.Data = mcpErr.Data
+	}
+	return body
+}
+
+// errorType names the category of an exit code for machine readable errors
+func errorType(code int) string {
+	switch code {
+	case ExitUsage:
+		return "usage"
+	case ExitConnection:
+		return "connection"
+	case ExitToolError:
+		return "tool"
+	default:
+		return "failure"
+	}
+}
+
+// writeRawContent writes only the payload of a content item
+func writeRawContent(w io.Writer, content entity.Content) {
+	switch {
+	case content.Type == "text":
+		fmt.Fprintln(w, content.Text)
+	case content.Resource != nil:
+		_ = writeResourceContents(w, *content.Resource)
+	case content.Data != nil:
+		if s, ok := content.Data.(string); ok {
+			fmt.Fprintln(w, strings.TrimSpace(s))
+		}
+	}
+}
+
+// toItems converts a slice to the element list written by jsonl
+func toItems[T any](values []T) []interface{} {
+	items := make([]interface{}, len(values))
+	for i, value := range values {
+		items[i] = value
+	}
+	return items
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/response"
+)
+
+// writeTools prints tools as a table
+func writeTools(out io.Writer, tools []entity.Tool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDESCRIPTION")
+	for _, tool := range tools {
+		fmt.Fprintf(w, "%s\t%s\n", tool.Name, firstLine(tool.Description))
+	}
+	return w.Flush()
+}
+
+// writeResources prints resources as a table
+func writeResources(out io.Writer, resources []entity.Resource) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "URI\tNAME\tMIME TYPE")
+	for _, resource := range resources {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", resource.URI, resource.Name, resource.MimeType)
+	}
+	return w.Flush()
+}
+
+// writePrompts prints prompts as a table; required arguments are marked with *
+func writePrompts(out io.Writer, prompts []entity.Prompt) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tARGUMENTS\tDESCRIPTION")
+	for _, prompt := range prompts {
+		names := make([]string, 0, len(prompt.Arguments))
+		for _, arg := range prompt.Arguments {
+			if arg.Required {
+				names = append(names, arg.Name+"*")
+			} else {
+				names = append(names, arg.Name)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", prompt.Name, strings.Join(names, ","), firstLine(prompt.Description))
+	}
+	return w.Flush()
+}
+
+// writeServerInfo prints server information and capabilities
+func writeServerInfo(out io.Writer, initResp *response.InitializeResponse) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Server:\t%s\n", initResp.ServerInfo.Name)
+	fmt.Fprintf(w, "Version:\t%s\n", initResp.ServerInfo.Version)
+	fmt.Fprintf(w, "Protocol:\t%s\n", initResp.ProtocolVersion)
+	fmt.Fprintf(w, "Capabilities:\t%s\n", strings.Join(capabilityNames(initResp.Capabilities), ", "))
+	if initResp.Instructions != "" {
+		fmt.Fprintf(w, "Instructions:\t%s\n", initResp.Instructions)
+	}
+	return w.Flush()
+}
+
+// writeToolResult prints the content of a tool result
+func writeToolResult(out io.Writer, result *entity.ToolResult) {
+	for _, content := range result.Content {
+		writeContent(out, content)
+	}
+}
+
+// writePromptResult prints the messages of a rendered prompt
+func writePromptResult(out io.Writer, result *entity.PromptResult) {
+	if result.Description != "" {
+		fmt.Fprintf(out, "# %s\n\n", result.Description)
+	}
+	for _, msg := range result.Messages {
+		fmt.Fprintf(out, "[%s]\n", msg.Role)
+		writeContent(out, msg.Content)
+		fmt.Fprintln(out)
+	}
+}
+
+// writeContent prints a single content item in a human readable form
+func writeContent(out io.Writer, content entity.Content) {
+	switch content.Type {
+	case "text":
+		fmt.Fprintln(out, content.Text)
+	case "image", "audio":
+		fmt.Fprintf(out, "[%s %s]\n", content.Type, content.MimeType)
+	case "resource":
+		if content.Resource != nil {
+			if err := writeResourceContents(out, *content.Resource); err == nil {
+				return
+			}
+		}
+		fmt.Fprintln(out, "[resource]")
+	default:
+		data, _ := json.Marshal(content)
+		fmt.Fprintln(out, string(data))
+	}
+}
+
+// writeResourceContents prints text contents as is and decodes blobs
+func writeResourceContents(out io.Writer, content entity.ResourceContents) error {
+	if content.Blob == "" {
+		fmt.Fprintln(out, content.Text)
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(content.Blob)
+	if err != nil {
+		return fmt.Errorf("failed to decode blob for %s: %w", content.URI, err)
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// capabilityNames returns the sorted names of the capabilities a server declared
+func capabilityNames(caps response.ServerCapabilities) []string {
+	names := []string{}
+	for name, value := range map[string]map[string]interface{}{
+		"tools":     caps.Tools,
+		"resources": caps.Resources,
+		"prompts":   caps.Prompts,
+		"logging":   caps.Logging,
+	} {
+		if value != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// firstLine returns the first line of a possibly multi-line description
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+// writeToolDetails prints a tool's description and the parameters of its input schema
+func writeToolDetails(out io.Writer, tool entity.Tool) error {
+	fmt.Fprintf(out, "%s\n", tool.Name)
+	if tool.Description != "" {
+		fmt.Fprintf(out, "\n%s\n", strings.TrimSpace(tool.Description))
+	}
+
+	params := schemaParameters(tool.InputSchema)
+	if len(params) == 0 {
+		fmt.Fprintln(out, "\nNo parameters.")
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PARAMETER\tTYPE\tREQUIRED\tDESCRIPTION")
+	for _, param := range params {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", param.name, param.typeName(), param.required, firstLine(param.description()))
+	}
+	return w.Flush()
+}
+
+// schemaParameter describes one property of an object JSON schema
+type schemaParameter struct {
+	name     string
+	schema   map[string]interface{}
+	required bool
+}
+
+// typeName returns the declared type, including enum choices when present
+func (p schemaParameter) typeName() string {
+	typeName, _ := p.schema["type"].(string)
+	if typeName == "" {
+		typeName = "any"
+	}
+	if enum, ok := p.schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		choices := make([]string, len(enum))
+		for i, choice := range enum {
+			choices[i] = fmt.Sprint(choice)
+		}
+		typeName += " [" + strings.Join(choices, "|") + "]"
+	}
+	return typeName
+}
+
+// description returns the property description
+func (p schemaParameter) description() string {
+	description, _ := p.schema["description"].(string)
+	return description
+}
+
+// schemaParameters lists the properties of an object schema, required ones first
+func schemaParameters(schema map[string]interface{}) []schemaParameter {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if names, ok := schema["required"].([]interface{}); ok {
+		for _, name := range names {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	params := make([]schemaParameter, 0, len(properties))
+	for name, value := range properties {
+		propSchema, _ := value.(map[string]interface{})
+		params = append(params, schemaParameter{name: name, schema: propSchema, required: required[name]})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].required != params[j].required {
+			return params[i].required
+		}
+		return params[i].name < params[j].name
+	})
+	return params
+}
@@ -0,0 +1,697 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/response"
+)
+
+const shellHelp = `Commands:
+  tools                           List the tools offered by the server
+  describe TOOL                   Show a tool's description and parameters
+  call TOOL [k=v]... [--json {}]  Call a tool; asks for each argument when none are given
+  resources                       List the resources offered by the server
+  read URI                        Print the contents of a resource
+  prompts                         List the prompts offered by the server
+  prompt NAME [k=v]...            Render a prompt; asks for missing required arguments
+  ping                            Check that the server responds
+  info                            Show server information and capabilities
+  help                            Show this help
+  exit                            Leave the shell
+`
+
+// lineReader reads shell input either from a terminal or from a pipe
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+	// Interrupt makes a pending ReadLine of a terminal return readline.ErrInterrupt
+	Interrupt()
+	Output() io.Writer
+	Close() error
+}
+
+// terminalReader reads lines with editing, completion and history
+type terminalReader struct {
+	rl    *readline.Instance
+	stdin *interruptibleStdin
+}
+
+// ReadLine implements lineReader
+func (r *terminalReader) ReadLine(prompt string) (string, error) {
+	r.rl.SetPrompt(prompt)
+	return r.rl.Readline()
+}
+
+// Interrupt implements lineReader
+func (r *terminalReader) Interrupt() {
+	r.stdin.Interrupt()
+}
+
+// Output implements lineReader
+func (r *terminalReader) Output() io.Writer {
+	return r.rl.Stdout()
+}
+
+// Close implements lineReader
+func (r *terminalReader) Close() error {
+	return r.rl.Close()
+}
+
+// interruptibleStdin is the input of the line editor. A read of the terminal
+// cannot be cancelled, so stdin is read in a goroutine and Interrupt hands the
+// editor a Ctrl-C instead.
+type interruptibleStdin struct {
+	chunks    chan stdinChunk
+	interrupt chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	pending   []byte
+}
+
+// stdinChunk is the result of one read of stdin
+type stdinChunk struct {
+	data []byte
+	err  error
+}
+
+// newInterruptibleStdin starts reading r
+func newInterruptibleStdin(r io.Reader) *interruptibleStdin {
+	s := &interruptibleStdin{
+		chunks:    make(chan stdinChunk),
+		interrupt: make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	go func() {
+		for {
+			buf := make([]byte, 1024)
+			n, err := r.Read(buf)
+			select {
+			case s.chunks <- stdinChunk{data: buf[:n], err: err}:
+			case <-s.done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// Read implements io.Reader
+func (s *interruptibleStdin) Read(p []byte) (int, error) {
+	if len(s.pending) == 0 {
+		select {
+		case chunk := <-s.chunks:
+			if len(chunk.data) == 0 {
+				return 0, chunk.err
+			}
+			s.pending = chunk.data
+		case <-s.interrupt:
+			p[0] = readline.CharInterrupt
+			return 1, nil
+		case <-s.done:
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Interrupt makes the pending or next read return Ctrl-C
+func (s *interruptibleStdin) Interrupt() {
+	select {
+	case s.interrupt <- struct{}{}:
+	default:
+	}
+}
+
+// Close implements io.Closer; the goroutine reading stdin ends with its next read
+func (s *interruptibleStdin) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return nil
+}
+
+// pipeReader reads lines from a non-interactive input such as a script
+type pipeReader struct {
+	scanner *bufio.Scanner
+	out     *syncWriter
+}
+
+// ReadLine implements lineReader
+func (r *pipeReader) ReadLine(prompt string) (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// Interrupt implements lineReader; a pipe is never read for approvals
+func (r *pipeReader) Interrupt() {}
+
+// Output implements lineReader
+func (r *pipeReader) Output() io.Writer {
+	return r.out
+}
+
+// Close implements lineReader
+func (r *pipeReader) Close() error {
+	return nil
+}
+
+// syncWriter serializes writes from the shell and the notification listener
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write implements io.Writer
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// shell holds the state of an interactive session
+type shell struct {
+	h           *CliHandler
+	in          lineReader
+	out         io.Writer
+	interactive bool
+	format      outputFormat
+	initResp    *response.InitializeResponse
+
+	mu      sync.Mutex
+	tools   []entity.Tool
+	prompts []entity.Prompt
+	uris    []string
+}
+
+// runShell starts a read-eval-print loop over a single session
+func (h *CliHandler) runShell(ctx context.Context, opts *globalOptions, args []string) error {
+	fs := newFlagSet("shell", opts)
+	historyFile := fs.String("history", defaultHistoryFile(), "File used to persist command history")
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+
+	initResp, err := h.connect(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer h.disconnect()
+
+	s := &shell{h: h, format: opts.output, initResp: initResp}
+	if readline.IsTerminal(int(os.Stdin.Fd())) {
+		stdin := newInterruptibleStdin(os.Stdin)
+		rl, err := readline.NewEx(&readline.Config{
+			Stdin:           stdin,
+			HistoryFile:     *historyFile,
+			AutoComplete:    s.completer(),
+			InterruptPrompt: "^C",
+			EOFPrompt:       "exit",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start line editor: %w", err)
+		}
+		s.in = &terminalReader{rl: rl, stdin: stdin}
+		s.interactive = true
+	} else {
+		s.in = &pipeReader{
+			scanner: bufio.NewScanner(os.Stdin),
+			out:     &syncWriter{w: h.stdout},
+		}
+	}
+	defer s.in.Close()
+	s.out = s.in.Output()
+	h.approvals.SetPrompter(s.promptApproval)
+
+	unsubscribe := h.mcpUsecase.SubscribeNotifications(s.printNotification)
+	defer unsubscribe()
+
+	if s.interactive {
+		fmt.Fprintf(s.out, "Connected to %s %s. Type help for a list of commands.\n",
+			initResp.ServerInfo.Name, initResp.ServerInfo.Version)
+	}
+	return s.loop(ctx)
+}
+
+// loop reads and executes commands until exit or end of input
+func (s *shell) loop(ctx context.Context) error {
+	failures := 0
+	for {
+		line, err := s.in.ReadLine("mcp> ")
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		tokens, err := splitArgs(line)
+		if err != nil {
+			s.printError(err)
+			failures++
+			continue
+		}
+		if tokens[0] == "exit" || tokens[0] == "quit" {
+			break
+		}
+
+		// Ctrl+C cancels the running command without leaving the shell
+		cmdCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT)
+		err = s.execute(cmdCtx, tokens)
+		stop()
+		if err != nil {
+			s.printError(err)
+			failures++
+		}
+	}
+
+	// Scripts piped into the shell should fail if any of their commands did
+	if !s.interactive && failures > 0 {
+		return fmt.Errorf("%d shell command(s) failed", failures)
+	}
+	return nil
+}
+
+// execute runs a single shell command
+func (s *shell) execute(ctx context.Context, tokens []string) error {
+	command, args := tokens[0], tokens[1:]
+	switch command {
+	case "help":
+		fmt.Fprint(s.out, shellHelp)
+		return nil
+	case "info":
+		return render(s.out, s.format, serverInfoView(s.initResp))
+	case "ping":
+		start := time.Now()
+		if err := s.h.mcpUsecase.Ping(ctx); err != nil {
+			return err
+		}
+		return render(s.out, s.format, pingView(s.initResp.ServerInfo.Name, time.Since(start)))
+	case "tools":
+		tools, err := s.loadTools(ctx)
+		if err != nil {
+			return err
+		}
+		return render(s.out, s.format, toolsView(tools))
+	case "describe":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: describe TOOL")
+		}
+		tool, err := s.findTool(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return render(s.out, s.format, toolDetailsView(tool))
+	case "call":
+		return s.callTool(ctx, args)
+	case "resources":
+		resources, err := s.h.mcpUsecase.GetAvailableResources(ctx)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.uris = s.uris[:0]
+		for _, resource := range resources {
+			s.uris = append(s.uris, resource.URI)
+		}
+		s.mu.Unlock()
+		return render(s.out, s.format, resourcesView(resources))
+	case "read":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: read URI")
+		}
+		contents, err := s.h.mcpUsecase.ReadResource(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return render(s.out, s.format, contentsView(contents))
+	case "prompts":
+		prompts, err := s.loadPrompts(ctx)
+		if err != nil {
+			return err
+		}
+		return render(s.out, s.format, promptsView(prompts))
+	case "prompt":
+		return s.getPrompt(ctx, args)
+	default:
+		return fmt.Errorf("unknown command %q, type help for a list of commands", command)
+	}
+}
+
+// printError reports a failed command in the shell's output format
+func (s *shell) printError(err error) {
+	_ = render(s.out, s.format, errorView(err))
+}
+
+// callTool calls a tool with arguments from the command line or asked interactively
+func (s *shell) callTool(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: call TOOL [k=v]... [--json {...}]")
+	}
+
+	name := args[0]
+	jsonArgs, kvArgs, err := parseShellArguments(args[1:], true)
+	if err != nil {
+		return err
+	}
+
+	tool, err := s.findTool(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var arguments map[string]interface{}
+	if jsonArgs == "" && len(kvArgs) == 0 && len(schemaParameters(tool.InputSchema)) > 0 {
+		arguments, err = s.askToolArguments(tool)
+	} else {
+		arguments, err = buildArguments(jsonArgs, kvArgs, tool.InputSchema)
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := s.h.mcpUsecase.ExecuteTool(ctx, entity.ToolCall{
+		Name:      name,
+		Arguments: arguments,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := render(s.out, s.format, toolResultView(result)); err != nil {
+		return err
+	}
+	if result.IsError {
+		return fmt.Errorf("tool %s reported an error", name)
+	}
+	return nil
+}
+
+// getPrompt renders a prompt, asking for required arguments that were not given
+func (s *shell) getPrompt(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: prompt NAME [k=v]...")
+	}
+
+	name := args[0]
+	_, promptArgs, err := parseShellArguments(args[1:], false)
+	if err != nil {
+		return err
+	}
+
+	prompts, err := s.loadPrompts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, prompt := range prompts {
+		if prompt.Name != name {
+			continue
+		}
+		askAll := len(promptArgs) == 0
+		for _, arg := range prompt.Arguments {
+			if _, given := promptArgs[arg.Name]; given || (!askAll && !arg.Required) {
+				continue
+			}
+			value, err := s.ask(arg.Name, "string", arg.Description, arg.Required)
+			if err != nil {
+				return err
+			}
+			if value != "" {
+				promptArgs[arg.Name] = value
+			}
+		}
+		break
+	}
+
+	result, err := s.h.mcpUsecase.GetPrompt(ctx, entity.PromptRequest{
+		Name:      name,
+		Arguments: promptArgs,
+	})
+	if err != nil {
+		return err
+	}
+
+	return render(s.out, s.format, promptResultView(result))
+}
+
+// askToolArguments builds tool arguments by asking for each schema property
+func (s *shell) askToolArguments(tool entity.Tool) (map[string]interface{}, error) {
+	arguments := make(map[string]interface{})
+	for _, param := range schemaParameters(tool.InputSchema) {
+		for {
+			raw, err := s.ask(param.name, param.typeName(), param.description(), param.required)
+			if err != nil {
+				return nil, err
+			}
+			if raw == "" {
+				break
+			}
+
+			value, err := coerceArgument(raw, param.schema)
+			if err != nil {
+				fmt.Fprintf(s.out, "  invalid %s: %v\n", param.typeName(), err)
+				continue
+			}
+			arguments[param.name] = value
+			break
+		}
+	}
+	return arguments, nil
+}
+
+// ask reads a single value; required values are asked for until one is given
+func (s *shell) ask(name, typeName, description string, required bool) (string, error) {
+	if description != "" && s.interactive {
+		fmt.Fprintf(s.out, "  # %s\n", firstLine(description))
+	}
+
+	label := fmt.Sprintf("  %s (%s): ", name, typeName)
+	if required {
+		label = fmt.Sprintf("  %s (%s, required): ", name, typeName)
+	}
+
+	for {
+		value, err := s.in.ReadLine(label)
+		if err != nil {
+			return "", fmt.Errorf("argument %s: %w", name, err)
+		}
+		value = strings.TrimSpace(value)
+		if value != "" || !required {
+			return value, nil
+		}
+		fmt.Fprintf(s.out, "  %s is required\n", name)
+	}
+}
+
+// printNotification shows server initiated messages inline
+func (s *shell) printNotification(msg *entity.Message) error {
+	if strings.HasSuffix(msg.Method, "/list_changed") {
+		s.mu.Lock()
+		switch msg.Method {
+		case "notifications/tools/list_changed":
+			s.tools = nil
+		case "notifications/prompts/list_changed":
+			s.prompts = nil
+		}
+		s.mu.Unlock()
+	}
+
+	if len(msg.Params) > 0 {
+		fmt.Fprintf(s.out, "<- %s %s\n", msg.Method, msg.Params)
+	} else {
+		fmt.Fprintf(s.out, "<- %s\n", msg.Method)
+	}
+	return nil
+}
+
+// loadTools fetches the tool list and caches it for completion
+func (s *shell) loadTools(ctx context.Context) ([]entity.Tool, error) {
+	tools, err := s.h.mcpUsecase.GetAvailableTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.tools = tools
+	s.mu.Unlock()
+	return tools, nil
+}
+
+// loadPrompts fetches the prompt list and caches it for completion
+func (s *shell) loadPrompts(ctx context.Context) ([]entity.Prompt, error) {
+	prompts, err := s.h.mcpUsecase.GetAvailablePrompts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.prompts = prompts
+	s.mu.Unlock()
+	return prompts, nil
+}
+
+// findTool returns a tool by name, using the cached list when possible
+func (s *shell) findTool(ctx context.Context, name string) (entity.Tool, error) {
+	s.mu.Lock()
+	tools := s.tools
+	s.mu.Unlock()
+
+	if tools == nil {
+		var err error
+		if tools, err = s.loadTools(ctx); err != nil {
+			return entity.Tool{}, err
+		}
+	}
+	for _, tool := range tools {
+		if tool.Name == name {
+			return tool, nil
+		}
+	}
+	return entity.Tool{}, fmt.Errorf("unknown tool %q", name)
+}
+
+// completer completes command names, tool names, prompt names and resource URIs
+func (s *shell) completer() readline.AutoCompleter {
+	toolNames := func(string) []string {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		names := make([]string, len(s.tools))
+		for i, tool := range s.tools {
+			names[i] = tool.Name
+		}
+		return names
+	}
+	promptNames := func(string) []string {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		names := make([]string, len(s.prompts))
+		for i, prompt := range s.prompts {
+			names[i] = prompt.Name
+		}
+		return names
+	}
+	uris := func(string) []string {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return append([]string(nil), s.uris...)
+	}
+
+	return readline.NewPrefixCompleter(
+		readline.PcItem("tools"),
+		readline.PcItem("describe", readline.PcItemDynamic(toolNames)),
+		readline.PcItem("call", readline.PcItemDynamic(toolNames)),
+		readline.PcItem("resources"),
+		readline.PcItem("read", readline.PcItemDynamic(uris)),
+		readline.PcItem("prompts"),
+		readline.PcItem("prompt", readline.PcItemDynamic(promptNames)),
+		readline.PcItem("ping"),
+		readline.PcItem("info"),
+		readline.PcItem("help"),
+		readline.PcItem("exit"),
+	)
+}
+
+// parseShellArguments splits shell command arguments into --json and key=value parts
+func parseShellArguments(args []string, allowJSON bool) (string, keyValueFlag, error) {
+	jsonArgs := ""
+	kvArgs := keyValueFlag{}
+	for i := 0; i < len(args); i++ {
+		if allowJSON && args[i] == "--json" {
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("--json requires a value")
+			}
+			i++
+			jsonArgs = args[i]
+			continue
+		}
+		if err := kvArgs.Set(args[i]); err != nil {
+			return "", nil, err
+		}
+	}
+	return jsonArgs, kvArgs, nil
+}
+
+// splitArgs splits a command line into words, honouring quotes and backslash escapes
+func splitArgs(line string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// defaultHistoryFile returns the path of the shell history file
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".mcpclient_history")
+}
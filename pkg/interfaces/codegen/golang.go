@@ -0,0 +1,465 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// entityImport is the import path of the entity package used by generated code
+const entityImport = "github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+
+// maxRefDepth limits $ref resolution so recursive schemas terminate
+const maxRefDepth = 8
+
+// GoOptions configures Go code generation
+type GoOptions struct {
+	// Package is the name of the generated package
+	Package string
+	// Server names the server the tools were read from, for the file header
+	Server string
+}
+
+// GenerateGo returns a formatted Go source file with typed arguments, typed results
+// and one wrapper method per tool
+func GenerateGo(opts GoOptions, tools []entity.Tool) ([]byte, error) {
+	if !token.IsIdentifier(opts.Package) || token.IsKeyword(opts.Package) {
+		return nil, fmt.Errorf("invalid package name %q", opts.Package)
+	}
+
+	sorted := make([]entity.Tool, len(tools))
+	copy(sorted, tools)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	g := &goGenerator{names: map[string]bool{
+		"Client":       true,
+		"New":          true,
+		"ToolExecutor": true,
+		"ToolError":    true,
+	}}
+	for _, tool := range sorted {
+		g.addTool(tool)
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by mcpclient gen go; DO NOT EDIT.\n")
+	if opts.Server != "" {
+		fmt.Fprintf(&src, "// Source: %s\n", opts.Server)
+	}
+	fmt.Fprintf(&src, "\npackage %s\n\n", opts.Package)
+	src.WriteString("import (\n\"context\"\n\"encoding/json\"\n\"fmt\"\n\n")
+	fmt.Fprintf(&src, "%q\n)\n\n", entityImport)
+	src.WriteString(clientSource)
+	src.Write(g.methods.Bytes())
+	src.Write(g.types.Bytes())
+	src.WriteString(helperSource)
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// goGenerator accumulates the declarations of a generated file
+type goGenerator struct {
+	types   bytes.Buffer
+	methods bytes.Buffer
+	names   map[string]bool
+	// prefix is the type name prefix of the current tool
+	prefix string
+	// refTypes maps the $ref targets of the current schema to their declared types
+	refTypes map[string]string
+	// expanding holds the $ref targets whose struct is being declared; a reference
+	// back to one of them becomes a pointer so the type does not contain itself
+	expanding map[string]bool
+}
+
+// addTool generates the argument and result types and the wrapper method of a tool
+func (g *goGenerator) addTool(tool entity.Tool) {
+	method := g.uniqueName(exportedName(tool.Name))
+	g.prefix = method
+
+	argsType := g.uniqueName(method + "Args")
+	input := tool.InputSchema
+	if input == nil {
+		input = map[string]interface{}{"type": "object"}
+	}
+	g.writeStruct(argsType, fmt.Sprintf("%s holds the arguments of the %s tool.", argsType, tool.Name), input, input)
+
+	resultType := "*entity.ToolResult"
+	decode := "return result, nil"
+	if tool.OutputSchema != nil {
+		name := g.uniqueName(method + "Result")
+		g.writeStruct(name, fmt.Sprintf("%s is the structured result of the %s tool.", name, tool.Name), tool.OutputSchema, tool.OutputSchema)
+		resultType = "*" + name
+		decode = fmt.Sprintf("var out %s\nif err := decodeResult(result, &out); err != nil {\nreturn nil, fmt.Errorf(\"failed to decode result of tool %%s: %%w\", %q, err)\n}\nreturn &out, nil", name, tool.Name)
+	}
+
+	writeComment(&g.methods, method+" calls the "+tool.Name+" tool.", tool.Description)
+	fmt.Fprintf(&g.methods, `func (c *Client) %s(ctx context.Context, args %s) (%s, error) {
+	result, err := c.call(ctx, %q, args)
+	if err != nil {
+		return nil, err
+	}
+	%s
+}
+
+`, method, argsType, resultType, tool.Name, decode)
+}
+
+// writeStruct declares a named type for an object schema
+func (g *goGenerator) writeStruct(name, doc string, schema, root map[string]interface{}) {
+	g.refTypes = make(map[string]string)
+	g.expanding = make(map[string]bool)
+	body := g.structBody(name, schema, root, 0)
+	writeComment(&g.types, doc, description(schema))
+	fmt.Fprintf(&g.types, "type %s %s\n\n", name, body)
+}
+
+// structBody returns the struct literal for an object schema; objects without
+// properties become maps
+func (g *goGenerator) structBody(name string, schema, root map[string]interface{}, depth int) string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return g.mapType(schema, root, name, depth)
+	}
+
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]interface{}); ok {
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	b.WriteString("struct {\n")
+	fields := make(map[string]bool)
+	for _, key := range keys {
+		prop, _ := properties[key].(map[string]interface{})
+		field := exportedName(key)
+		for i := 2; fields[field]; i++ {
+			field = exportedName(key) + strconv.Itoa(i)
+		}
+		fields[field] = true
+
+		typ, nullable := g.goType(name+field, prop, root, depth)
+		tag := key
+		if !required[key] {
+			tag += ",omitempty"
+		}
+		// Optional and nullable values need a pointer to tell zero from absent
+		if (!required[key] || nullable) && needsPointer(typ) {
+			typ = "*" + typ
+		}
+
+		writeComment(&b, "", description(prop))
+		fmt.Fprintf(&b, "%s %s `json:%q`\n", field, typ, tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// goType maps a property schema to a Go type. Enums and nested objects become
+// named types. nullable reports whether the schema allows null.
+func (g *goGenerator) goType(name string, schema, root map[string]interface{}, depth int) (typ string, nullable bool) {
+	ref, _ := schema["$ref"].(string)
+	if typeName, ok := g.refTypes[ref]; ok {
+		if g.expanding[ref] {
+			return "*" + typeName, false
+		}
+		return typeName, false
+	}
+	if ref != "" {
+		// Definitions are named after themselves so that recursive schemas terminate
+		name = g.prefix + exportedName(path.Base(ref))
+	}
+
+	schema, ok := resolveRef(schema, root, depth)
+	if !ok {
+		return "interface{}", false
+	}
+
+	kind, nullable := schemaType(schema)
+	switch kind {
+	case "string":
+		if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+			return g.enumType(name, schema, enum), nullable
+		}
+		return "string", nullable
+	case "integer":
+		return "int64", nullable
+	case "number":
+		return "float64", nullable
+	case "boolean":
+		return "bool", nullable
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		if items == nil {
+			return "[]interface{}", nullable
+		}
+		elem, _ := g.goType(name+"Item", items, root, depth+1)
+		return "[]" + elem, nullable
+	case "object":
+		if properties, _ := schema["properties"].(map[string]interface{}); len(properties) == 0 {
+			return g.mapType(schema, root, name, depth), nullable
+		}
+		typeName := g.uniqueName(name)
+		if ref != "" {
+			g.refTypes[ref] = typeName
+			g.expanding[ref] = true
+			defer delete(g.expanding, ref)
+		}
+		g.writeNested(typeName, schema, root, depth+1)
+		return typeName, nullable
+	default:
+		return "interface{}", nullable
+	}
+}
+
+// writeNested declares a named struct for a nested object schema
+func (g *goGenerator) writeNested(name string, schema, root map[string]interface{}, depth int) {
+	body := g.structBody(name, schema, root, depth)
+	writeComment(&g.types, name+" is a nested object.", description(schema))
+	fmt.Fprintf(&g.types, "type %s %s\n\n", name, body)
+}
+
+// mapType maps an object without properties to a map, typed by additionalProperties when given
+func (g *goGenerator) mapType(schema, root map[string]interface{}, name string, depth int) string {
+	additional, ok := schema["additionalProperties"].(map[string]interface{})
+	if !ok || len(additional) == 0 {
+		return "map[string]interface{}"
+	}
+	elem, _ := g.goType(name+"Value", additional, root, depth+1)
+	return "map[string]" + elem
+}
+
+// enumType declares a string type with one constant per enum value
+func (g *goGenerator) enumType(name string, schema map[string]interface{}, enum []interface{}) string {
+	typeName := g.uniqueName(name)
+	writeComment(&g.types, typeName+" is one of the allowed values of an enum.", description(schema))
+	fmt.Fprintf(&g.types, "type %s string\n\n", typeName)
+
+	g.types.WriteString("const (\n")
+	for _, value := range enum {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		constName := g.uniqueName(typeName + exportedName(s))
+		fmt.Fprintf(&g.types, "%s %s = %q\n", constName, typeName, s)
+	}
+	g.types.WriteString(")\n\n")
+	return typeName
+}
+
+// uniqueName returns name, or name with a numeric suffix when it is already taken
+func (g *goGenerator) uniqueName(name string) string {
+	candidate := name
+	for i := 2; g.names[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	g.names[candidate] = true
+	return candidate
+}
+
+// resolveRef follows a local $ref such as #/$defs/Name within the root schema
+func resolveRef(schema, root map[string]interface{}, depth int) (map[string]interface{}, bool) {
+	for depth <= maxRefDepth {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema, true
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, false
+		}
+
+		var node interface{} = root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			obj, ok := node.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			node = obj[part]
+		}
+		target, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		schema = target
+		depth++
+	}
+	return nil, false
+}
+
+// schemaType returns the JSON type of a schema and whether it also allows null
+func schemaType(schema map[string]interface{}) (string, bool) {
+	switch t := schema["type"].(type) {
+	case string:
+		return t, false
+	case []interface{}:
+		kind, nullable := "", false
+		for _, item := range t {
+			s, _ := item.(string)
+			switch {
+			case s == "null":
+				nullable = true
+			case kind == "":
+				kind = s
+			default:
+				// Several non-null types cannot be expressed as one Go type
+				return "", nullable
+			}
+		}
+		return kind, nullable
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object", false
+	}
+	return "", false
+}
+
+// needsPointer reports whether absence of a value of typ cannot be expressed with nil
+func needsPointer(typ string) bool {
+	return !strings.HasPrefix(typ, "*") && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && typ != "interface{}"
+}
+
+// exportedName converts a JSON name such as get_weather or max-results to GetWeather and MaxResults
+func exportedName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	s := b.String()
+	if s == "" {
+		return "Value"
+	}
+	if unicode.IsDigit(rune(s[0])) {
+		return "X" + s
+	}
+	return s
+}
+
+// description returns the description of a schema
+func description(schema map[string]interface{}) string {
+	s, _ := schema["description"].(string)
+	return strings.TrimSpace(s)
+}
+
+// writeComment writes a doc comment from a summary line and an optional description
+func writeComment(b *bytes.Buffer, summary, desc string) {
+	var lines []string
+	if summary != "" {
+		lines = append(lines, summary)
+	}
+	if desc != "" {
+		if summary != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, strings.Split(desc, "\n")...)
+	}
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+}
+
+// clientSource declares the client type shared by every generated method
+const clientSource = `// ToolExecutor executes tool calls; *usecase.MCPUsecase implements it.
+type ToolExecutor interface {
+	ExecuteTool(ctx context.Context, toolCall entity.ToolCall) (*entity.ToolResult, error)
+}
+
+// Client calls the tools of an MCP server with typed arguments and results.
+type Client struct {
+	executor ToolExecutor
+}
+
+// New creates a client that sends tool calls through executor.
+func New(executor ToolExecutor) *Client {
+	return &Client{executor: executor}
+}
+
+// ToolError is returned when a tool reports a failure with isError.
+type ToolError struct {
+	Tool   string
+	Result *entity.ToolResult
+}
+
+// Error implements error.
+func (e *ToolError) Error() string {
+	for _, content := range e.Result.Content {
+		if content.Type == "text" {
+			return fmt.Sprintf("tool %s failed: %s", e.Tool, content.Text)
+		}
+	}
+	return fmt.Sprintf("tool %s failed", e.Tool)
+}
+
+`
+
+// helperSource declares the helpers used by the generated methods
+const helperSource = `// call converts typed arguments and executes a tool.
+func (c *Client) call(ctx context.Context, name string, args interface{}) (*entity.ToolResult, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal arguments of tool %s: %w", name, err)
+	}
+	arguments := make(map[string]interface{})
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		return nil, fmt.Errorf("failed to convert arguments of tool %s: %w", name, err)
+	}
+
+	result, err := c.executor.ExecuteTool(ctx, entity.ToolCall{Name: name, Arguments: arguments})
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return nil, &ToolError{Tool: name, Result: result}
+	}
+	return result, nil
+}
+
+// decodeResult decodes structuredContent, or the first JSON text content, into out.
+func decodeResult(result *entity.ToolResult, out interface{}) error {
+	if result.StructuredContent != nil {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, out)
+	}
+	for _, content := range result.Content {
+		if content.Type == "text" {
+			return json.Unmarshal([]byte(content.Text), out)
+		}
+	}
+	return fmt.Errorf("result has no structured content")
+}
+`
@@ -0,0 +1,96 @@
+package codegen
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// generate decodes an input schema and generates the package of one tool with it
+func generate(t *testing.T, inputSchema string) string {
+	t.Helper()
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(inputSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+	src, err := GenerateGo(GoOptions{Package: "tools"}, []entity.Tool{{Name: "tree", InputSchema: schema}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(src)
+}
+
+// typeCheck fails the test when the generated source does not compile
+func typeCheck(t *testing.T, src string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "tools.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := config.Check("tools", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("generated code does not compile: %v\n%s", err, src)
+	}
+}
+
+func TestGenerateGoRecursiveRef(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []string
+	}{
+		{
+			name: "self reference",
+			schema: `{"type":"object","required":["root"],"properties":{"root":{"$ref":"#/$defs/node"}},
+				"$defs":{"node":{"type":"object","required":["name","parent"],"properties":{
+					"name":{"type":"string"},
+					"parent":{"$ref":"#/$defs/node"},
+					"children":{"type":"array","items":{"$ref":"#/$defs/node"}}}}}}`,
+			want: []string{
+				"Root TreeNode `json:\"root\"`",
+				"Parent   *TreeNode   `json:\"parent\"`",
+				"Children []*TreeNode `json:\"children,omitempty\"`",
+			},
+		},
+		{
+			name: "mutual reference",
+			schema: `{"type":"object","properties":{"a":{"$ref":"#/$defs/a"}},
+				"$defs":{
+					"a":{"type":"object","required":["b"],"properties":{"b":{"$ref":"#/$defs/b"}}},
+					"b":{"type":"object","required":["a"],"properties":{"a":{"$ref":"#/$defs/a"}}}}}`,
+			want: []string{
+				"A *TreeA `json:\"a,omitempty\"`",
+				"B TreeB `json:\"b\"`",
+				"A *TreeA `json:\"a\"`",
+			},
+		},
+		{
+			name: "repeated reference that is not recursive",
+			schema: `{"type":"object","required":["from","to"],"properties":{"from":{"$ref":"#/$defs/point"},"to":{"$ref":"#/$defs/point"}},
+				"$defs":{"point":{"type":"object","required":["x"],"properties":{"x":{"type":"number"}}}}}`,
+			want: []string{
+				"From TreePoint `json:\"from\"`",
+				"To   TreePoint `json:\"to\"`",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := generate(t, tt.schema)
+			for _, want := range tt.want {
+				if !strings.Contains(src, want) {
+					t.Errorf("generated code lacks %s\n%s", want, src)
+				}
+			}
+			typeCheck(t, src)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/usecase"
+)
+
+// errUnauthenticatedLister is returned when an unauthenticated caller lists the approval queue
+var errUnauthenticatedLister = errors.New("listing approvals requires an authenticated caller")
+
+// handleListApprovals serves GET /approvals, the tool calls waiting for approval.
+// Only callers who may decide them can see them.
+func (h *HTTPHandler) handleListApprovals(w http.ResponseWriter, r *http.Request) {
+	if !usecase.CallerAuthenticated(r.Context()) {
+		writeUnauthorized(w, errUnauthenticatedLister)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"approvals": h.approvals.PendingApprovals(r.Context())})
+}
+
+// handleApprove serves POST /approvals/{id}/approve
+func (h *HTTPHandler) handleApprove(w http.ResponseWriter, r *http.Request) {
+	h.decideApproval(w, r, true)
+}
+
+// handleDeny serves POST /approvals/{id}/deny
+func (h *HTTPHandler) handleDeny(w http.ResponseWriter, r *http.Request) {
+	h.decideApproval(w, r, false)
+}
+
+// decideApproval answers a pending approval request on behalf of the caller,
+// who must have been authenticated
+func (h *HTTPHandler) decideApproval(w http.ResponseWriter, r *http.Request, approved bool) {
+	id := r.PathValue("id")
+	approval, err := h.approvals.DecideApproval(r.Context(), id, approved)
+	switch {
+	case errors.Is(err, usecase.ErrUnauthenticatedApprover):
+		writeUnauthorized(w, err)
+	case errors.Is(err, usecase.ErrApprovalNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, usecase.ErrSelfApproval):
+		writeError(w, http.StatusForbidden, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": id, "decision": approval.Decision, "approver": approval.Approver})
+	}
+}
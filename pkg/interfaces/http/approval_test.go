@@ -0,0 +1,197 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/usecase"
+)
+
+// requestApproval queues an approval request made by caller and returns its ID
+// with a channel that receives the decision
+func requestApproval(t *testing.T, approvals *usecase.ApprovalUsecase, caller string) (string, <-chan entity.Approval) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(usecase.WithAuthenticatedCaller(context.Background(), caller))
+	t.Cleanup(cancel)
+	decided := make(chan entity.Approval, 1)
+	go func() {
+		approval, _ := approvals.RequestApproval(ctx, entity.ApprovalRequest{Tool: "delete_file", Caller: caller})
+		decided <- approval
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if pending := approvals.PendingApprovals(context.Background()); len(pending) == 1 {
+			return pending[0].ID, decided
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("the approval request was not queued")
+	return "", nil
+}
+
+func TestApproveRequiresAuthenticatedCaller(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		prepare    func(r *http.Request)
+		wantStatus int
+		wantBy     string
+	}{
+		{
+			name:       "forged X-Forwarded-User",
+			remoteAddr: "192.0.2.10:40000",
+			prepare:    func(r *http.Request) { r.Header.Set("X-Forwarded-User", "alice") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "forged X-Remote-User",
+			remoteAddr: "192.0.2.10:40000",
+			prepare:    func(r *http.Request) { r.Header.Set("X-Remote-User", "alice") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong password",
+			remoteAddr: "192.0.2.10:40000",
+			prepare:    func(r *http.Request) { r.SetBasicAuth("alice", "guess") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "anonymous",
+			remoteAddr: "192.0.2.10:40000",
+			prepare:    func(r *http.Request) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "basic authentication",
+			remoteAddr: "192.0.2.10:40000",
+			prepare:    func(r *http.Request) { r.SetBasicAuth("alice", "s3cret") },
+			wantStatus: http.StatusOK,
+			wantBy:     "http:alice",
+		},
+		{
+			name:       "trusted proxy",
+			remoteAddr: "10.1.2.3:40000",
+			prepare:    func(r *http.Request) { r.Header.Set("X-Forwarded-User", "carol") },
+			wantStatus: http.StatusOK,
+			wantBy:     "http:carol",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approvals := usecase.NewApprovalUsecase()
+			approvals.SetTimeout(5 * time.Second)
+			h := NewHTTPHandler(nil, nil, approvals, nil)
+			if err := h.SetAuth(config.HTTPConfig{
+				Users:          map[string]string{"alice": "s3cret"},
+				TrustedProxies: []string{"10.0.0.0/8"},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			id, decided := requestApproval(t, approvals, "http:dave")
+
+			req := httptest.NewRequest(http.MethodPost, "/approvals/"+id+"/approve", nil)
+			req.RemoteAddr = tt.remoteAddr
+			tt.prepare(req)
+			rec := httptest.NewRecorder()
+			h.Routes().ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if pending := approvals.PendingApprovals(context.Background()); len(pending) != 1 {
+					t.Fatalf("the request was decided by an unauthenticated caller")
+				}
+				return
+			}
+			select {
+			case approval := <-decided:
+				if approval.Decision != entity.ApprovalApproved || approval.Approver != tt.wantBy {
+					t.Errorf("approval = %+v, want approved by %s", approval, tt.wantBy)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("the tool call was not released")
+			}
+		})
+	}
+}
+
+func TestListApprovalsRequiresAuthenticatedCaller(t *testing.T) {
+	approvals := usecase.NewApprovalUsecase()
+	approvals.SetTimeout(5 * time.Second)
+	h := NewHTTPHandler(nil, nil, approvals, nil)
+	if err := h.SetAuth(config.HTTPConfig{Users: map[string]string{"alice": "s3cret"}}); err != nil {
+		t.Fatal(err)
+	}
+	requestApproval(t, approvals, "http:dave")
+
+	tests := []struct {
+		name       string
+		prepare    func(r *http.Request)
+		wantStatus int
+	}{
+		{name: "anonymous", prepare: func(r *http.Request) {}, wantStatus: http.StatusUnauthorized},
+		{name: "forged X-Forwarded-User", prepare: func(r *http.Request) { r.Header.Set("X-Forwarded-User", "alice") }, wantStatus: http.StatusUnauthorized},
+		{name: "basic authentication", prepare: func(r *http.Request) { r.SetBasicAuth("alice", "s3cret") }, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/approvals", nil)
+			req.RemoteAddr = "192.0.2.10:40000"
+			tt.prepare(req)
+			rec := httptest.NewRecorder()
+			h.Routes().ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if listed := strings.Contains(rec.Body.String(), "delete_file"); listed != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("body = %s, want the request listed only to an authenticated caller", rec.Body)
+			}
+		})
+	}
+}
+
+func TestIdentifyCaller(t *testing.T) {
+	auth, err := newAuthenticator(config.HTTPConfig{
+		Users:          map[string]string{"alice": "s3cret"},
+		TrustedProxies: []string{"10.0.0.0/8"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		header       string
+		value        string
+		wantCaller   string
+		wantVerified bool
+	}{
+		{"untrusted peer", "192.0.2.10:40000", "X-Forwarded-User", "alice", `192.0.2.10 (unverified: "alice")`, false},
+		{"trusted proxy", "10.0.0.1:40000", "X-Remote-User", "alice", "alice", true},
+		{"no claim", "192.0.2.10:40000", "", "", "192.0.2.10", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tools", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			caller, verified, ok := auth.identify(req)
+			if !ok || caller != tt.wantCaller || verified != tt.wantVerified {
+				t.Errorf("identify = %q, %v, %v; want %q, %v, true", caller, verified, ok, tt.wantCaller, tt.wantVerified)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/usecase"
+)
+
+// authRealm is the realm of the basic authentication challenge
+const authRealm = "mcp-client"
+
+// authenticator verifies who makes a request from the http section of the configuration
+type authenticator struct {
+	// users holds the SHA-256 hash of each password so that comparisons take the same time
+	users   map[string][32]byte
+	proxies []*net.IPNet
+}
+
+// newAuthenticator parses the users and trusted proxies of the configuration
+func newAuthenticator(cfg config.HTTPConfig) (*authenticator, error) {
+	auth := &authenticator{users: make(map[string][32]byte, len(cfg.Users))}
+	for name, password := range cfg.Users {
+		auth.users[name] = sha256.Sum256([]byte(password))
+	}
+	for _, cidr := range cfg.TrustedProxies {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("http.trusted_proxies: %q is not a CIDR", cidr)
+		}
+		auth.proxies = append(auth.proxies, network)
+	}
+	return auth, nil
+}
+
+// SetAuth replaces how callers are authenticated; an invalid configuration keeps the previous one
+func (h *HTTPHandler) SetAuth(cfg config.HTTPConfig) error {
+	auth, err := newAuthenticator(cfg)
+	if err != nil {
+		return err
+	}
+	h.authMu.Lock()
+	h.auth = auth
+	h.authMu.Unlock()
+	return nil
+}
+
+// identify returns the caller of a request and whether its identity was verified.
+// ok is false for basic authentication credentials that are wrong.
+func (a *authenticator) identify(r *http.Request) (caller string, verified, ok bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	claimed := ""
+	if user, password, hasAuth := r.BasicAuth(); hasAuth {
+		if len(a.users) > 0 {
+			if !a.checkPassword(user, password) {
+				return "", false, false
+			}
+			return user, true, true
+		}
+		claimed = user
+	}
+	for _, header := range []string{"X-Forwarded-User", "X-Remote-User"} {
+		user := strings.TrimSpace(r.Header.Get(header))
+		if user == "" {
+			continue
+		}
+		if a.trustedProxy(host) {
+			return user, true, true
+		}
+		if claimed == "" {
+			claimed = user
+		}
+	}
+
+	if claimed != "" {
+		return fmt.Sprintf("%s (unverified: %q)", host, claimed), false, true
+	}
+	return host, false, true
+}
+
+// checkPassword compares a password in constant time
+func (a *authenticator) checkPassword(user, password string) bool {
+	want, known := a.users[user]
+	got := sha256.Sum256([]byte(password))
+	return subtle.ConstantTimeCompare(want[:], got[:]) == 1 && known
+}
+
+// trustedProxy reports whether a peer address is one of the trusted proxies
+func (a *authenticator) trustedProxy(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range a.proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// identifyCaller records who makes a request for the audit log. The user of
+// basic authentication counts when it matches http.users, and the user forwarded
+// in X-Forwarded-User or X-Remote-User when the peer is a trusted proxy. Anyone
+// else is identified by the remote address, followed by the user it claimed.
+func (h *HTTPHandler) identifyCaller(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.authMu.RLock()
+		auth := h.auth
+		h.authMu.RUnlock()
+
+		caller, verified, ok := auth.identify(r)
+		if !ok {
+			writeUnauthorized(w, fmt.Errorf("invalid user name or password"))
+			return
+		}
+		ctx := usecase.WithCaller(r.Context(), "http:"+caller)
+		if verified {
+			ctx = usecase.WithAuthenticatedCaller(r.Context(), "http:"+caller)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// writeUnauthorized writes a 401 response that asks for basic authentication
+func writeUnauthorized(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", authRealm))
+	writeError(w, http.StatusUnauthorized, err)
+}
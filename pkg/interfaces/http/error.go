@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/usecase"
+)
+
+// JSON-RPC error codes that map to client errors
+const (
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+	codeResourceNotFound = -32002
+)
+
+// errorResponse is the body of every error response
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+// errorBody describes an error; code and data come from the MCP server when present
+type errorBody struct {
+	Status  int         `json:"status"`
+	Message string      `json:"message"`
+	Code    int         `json:"code,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warn("failed to write response", "error", err)
+	}
+}
+
+// writeError writes an error response with the given status
+func writeError(w http.ResponseWriter, status int, err error) {
+	body := errorBody{
+		Status:  status,
+		Message: err.Error(),
+	}
+
+	var mcpErr *entity.Error
+	if errors.As(err, &mcpErr) {
+		body.Code = mcpErr.Code
+		body.Data = mcpErr.Data
+	}
+	writeJSON(w, status, errorResponse{Error: body})
+}
+
+// writeUsecaseError maps an error returned by the usecase layer to an HTTP status
+func writeUsecaseError(w http.ResponseWriter, err error) {
+	if errors.Is(err, usecase.ErrUnauthenticatedCaller) {
+		writeUnauthorized(w, err)
+		return
+	}
+	writeError(w, statusForError(err), err)
+}
+
+// statusForError chooses the HTTP status for a usecase error
+func statusForError(err error) int {
+	var mcpErr *entity.Error
+	var policyErr *usecase.PolicyError
+	var approvalErr *usecase.ApprovalError
+	switch {
+	case errors.As(err, &policyErr), errors.As(err, &approvalErr):
+		return http.StatusForbidden
+	case errors.Is(err, usecase.ErrToolNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &mcpErr):
+		switch mcpErr.Code {
+		case codeMethodNotFound, codeResourceNotFound:
+			return http.StatusNotFound
+		case codeInvalidParams:
+			return http.StatusBadRequest
+		default:
+			return http.StatusBadGateway
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	case strings.Contains(err.Error(), "not connected"):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
@@ -0,0 +1,270 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+const (
+	// eventBufferSize is the number of past events kept for Last-Event-ID resume
+	eventBufferSize = 1024
+	// subscriberBufferSize is the number of events queued for a slow client before it is dropped
+	subscriberBufferSize = 64
+	// keepAliveInterval is how often an idle stream sends a comment to keep proxies from closing it
+	keepAliveInterval = 15 * time.Second
+	// retryInterval is the reconnect delay suggested to clients
+	retryInterval = 3 * time.Second
+)
+
+// SSE event types
+const (
+	eventProgress        = "progress"
+	eventLog             = "log"
+	eventListChanged     = "list_changed"
+	eventResourceUpdated = "resource_updated"
+	eventConnection      = "connection"
+	eventNotification    = "notification"
+	// eventReset tells a resuming client that events after its Last-Event-ID are
+	// no longer available, because the gateway restarted or they were evicted
+	eventReset = "reset"
+)
+
+// Reasons given by a reset event
+const (
+	resetUnknownID = "unknown_id"
+	resetEvicted   = "evicted"
+)
+
+// event is a single Server-Sent Event
+type event struct {
+	ID   uint64
+	Type string
+	Data json.RawMessage
+}
+
+// eventBroker fans events out to SSE clients and keeps a bounded history for resume.
+// Event IDs are EPOCH-SEQ; the epoch changes with every broker so that IDs of a
+// previous gateway process are recognized as unknown.
+type eventBroker struct {
+	epoch       string
+	mu          sync.Mutex
+	nextID      uint64
+	buffer      []event
+	start       int
+	subscribers map[chan event]struct{}
+}
+
+// newEventBroker creates a broker that keeps the last size events
+func newEventBroker(size int) *eventBroker {
+	return &eventBroker{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		buffer:      make([]event, 0, size),
+		subscribers: make(map[chan event]struct{}),
+	}
+}
+
+// publish assigns the next ID to an event, stores it and sends it to every subscriber.
+// Subscribers that cannot keep up are dropped; they resume with Last-Event-ID.
+func (b *eventBroker) publish(eventType string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		logger.Warn("failed to marshal event", "event", eventType, "error", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	ev := event{ID: b.nextID, Type: eventType, Data: payload}
+	if len(b.buffer) < cap(b.buffer) {
+		b.buffer = append(b.buffer, ev)
+	} else {
+		b.buffer[b.start] = ev
+		b.start = (b.start + 1) % len(b.buffer)
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe returns the buffered events after lastID and a channel for new events.
+// Both are taken under one lock so no event is missed or repeated. When lastID
+// is set but the events after it cannot be replayed, reset says why and the
+// whole buffer is returned.
+func (b *eventBroker) subscribe(lastID eventID) (backlog []event, ch chan event, reset string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	after := lastID.seq
+	if lastID.set {
+		oldest := b.nextID + 1
+		if len(b.buffer) > 0 {
+			oldest = b.buffer[b.start].ID
+		}
+		switch {
+		case lastID.epoch != b.epoch || lastID.seq > b.nextID:
+			reset, after = resetUnknownID, 0
+		case lastID.seq+1 < oldest:
+			reset, after = resetEvicted, 0
+		}
+	}
+
+	for i := range b.buffer {
+		ev := b.buffer[(b.start+i)%len(b.buffer)]
+		if ev.ID > after {
+			backlog = append(backlog, ev)
+		}
+	}
+
+	ch = make(chan event, subscriberBufferSize)
+	b.subscribers[ch] = struct{}{}
+	return backlog, ch, reset
+}
+
+// unsubscribe removes a subscriber unless it was already dropped
+func (b *eventBroker) unsubscribe(ch chan event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// publishMessage publishes a server notification; server requests are not forwarded
+func (b *eventBroker) publishMessage(msg *entity.Message) error {
+	if msg.ID != "" {
+		return nil
+	}
+	b.publish(notificationEventType(msg.Method), struct {
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params,omitempty"`
+	}{msg.Method, msg.Params})
+	return nil
+}
+
+// publishConnection publishes a connection state change
+func (b *eventBroker) publishConnection(conn entity.Connection) {
+	b.publish(eventConnection, conn)
+}
+
+// notificationEventType maps a notification method to its SSE event type
+func notificationEventType(method string) string {
+	switch {
+	case method == "notifications/progress":
+		return eventProgress
+	case method == "notifications/message":
+		return eventLog
+	case method == "notifications/resources/updated":
+		return eventResourceUpdated
+	case strings.HasSuffix(method, "/list_changed"):
+		return eventListChanged
+	default:
+		return eventNotification
+	}
+}
+
+// handleEvents serves GET /events as a Server-Sent Events stream.
+// Clients resume after a reconnect with the Last-Event-ID header or the lastEventId query parameter.
+func (h *HTTPHandler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	lastID, err := lastEventID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	backlog, ch, reset := h.events.subscribe(lastID)
+	defer h.events.unsubscribe(ch)
+
+	fmt.Fprintf(w, "retry: %d\n\n", retryInterval.Milliseconds())
+	if reset != "" {
+		data, _ := json.Marshal(map[string]string{"reason": reset, "lastEventId": lastID.raw})
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventReset, data)
+	}
+	for _, ev := range backlog {
+		writeEvent(w, h.events.epoch, ev)
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Warn("streaming is not supported", "error", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-ch:
+			if !ok {
+				// Dropped for being too slow; the client reconnects and resumes
+				return
+			}
+			writeEvent(w, h.events.epoch, ev)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// eventID is a parsed Last-Event-ID
+type eventID struct {
+	set   bool
+	raw   string
+	epoch string
+	seq   uint64
+}
+
+// lastEventID reads the ID of the last event the client has seen. An ID without
+// an epoch, such as one from an older gateway, is kept as unknown.
+func lastEventID(r *http.Request) (eventID, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return eventID{}, nil
+	}
+
+	id := eventID{set: true, raw: value}
+	seq := value
+	if i := strings.LastIndexByte(value, '-'); i >= 0 {
+		id.epoch, seq = value[:i], value[i+1:]
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return eventID{}, fmt.Errorf("invalid Last-Event-ID %q", value)
+	}
+	id.seq = n
+	return id, nil
+}
+
+// writeEvent writes one event in the SSE wire format
+func writeEvent(w http.ResponseWriter, epoch string, ev event) {
+	fmt.Fprintf(w, "id: %s-%d\nevent: %s\ndata: %s\n\n", epoch, ev.ID, ev.Type, ev.Data)
+}
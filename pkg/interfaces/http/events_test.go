@@ -0,0 +1,104 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestBroker returns a broker keeping size events with published events 1..n
+func newTestBroker(size, n int) *eventBroker {
+	b := newEventBroker(size)
+	for i := 0; i < n; i++ {
+		b.publish(eventNotification, i)
+	}
+	return b
+}
+
+// eventIDs returns the IDs of events
+func eventIDs(events []event) []uint64 {
+	ids := []uint64{}
+	for _, ev := range events {
+		ids = append(ids, ev.ID)
+	}
+	return ids
+}
+
+func TestEventBrokerSubscribe(t *testing.T) {
+	b := newTestBroker(4, 6)
+
+	tests := []struct {
+		name      string
+		lastID    eventID
+		wantIDs   []uint64
+		wantReset string
+	}{
+		{"no Last-Event-ID", eventID{}, []uint64{3, 4, 5, 6}, ""},
+		{"resume", eventID{set: true, epoch: b.epoch, seq: 4}, []uint64{5, 6}, ""},
+		{"resume just before the oldest event", eventID{set: true, epoch: b.epoch, seq: 2}, []uint64{3, 4, 5, 6}, ""},
+		{"up to date", eventID{set: true, epoch: b.epoch, seq: 6}, []uint64{}, ""},
+		{"evicted", eventID{set: true, epoch: b.epoch, seq: 1}, []uint64{3, 4, 5, 6}, resetEvicted},
+		{"other epoch", eventID{set: true, epoch: "previous", seq: 4}, []uint64{3, 4, 5, 6}, resetUnknownID},
+		{"no epoch", eventID{set: true, seq: 4}, []uint64{3, 4, 5, 6}, resetUnknownID},
+		{"beyond the last event", eventID{set: true, epoch: b.epoch, seq: 7}, []uint64{3, 4, 5, 6}, resetUnknownID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backlog, ch, reset := b.subscribe(tt.lastID)
+			defer b.unsubscribe(ch)
+			if got := eventIDs(backlog); fmt.Sprint(got) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("backlog = %v, want %v", got, tt.wantIDs)
+			}
+			if reset != tt.wantReset {
+				t.Errorf("reset = %q, want %q", reset, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestEventBrokerSubscribeEmpty(t *testing.T) {
+	b := newTestBroker(4, 0)
+	backlog, ch, reset := b.subscribe(eventID{set: true, epoch: b.epoch, seq: 0})
+	defer b.unsubscribe(ch)
+	if len(backlog) != 0 || reset != "" {
+		t.Errorf("subscribe = %v, %q; want nothing to replay", eventIDs(backlog), reset)
+	}
+
+	// Events published after subscribing arrive on the channel
+	b.publish(eventLog, "hello")
+	if ev := <-ch; ev.ID != 1 || ev.Type != eventLog {
+		t.Errorf("event = %+v, want the first log event", ev)
+	}
+}
+
+func TestLastEventID(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		query   string
+		want    eventID
+		wantErr bool
+	}{
+		{"absent", "", "", eventID{}, false},
+		{"header", "lsk2x1c0-42", "", eventID{set: true, raw: "lsk2x1c0-42", epoch: "lsk2x1c0", seq: 42}, false},
+		{"query", "", "lsk2x1c0-7", eventID{set: true, raw: "lsk2x1c0-7", epoch: "lsk2x1c0", seq: 7}, false},
+		{"without epoch", "42", "", eventID{set: true, raw: "42", seq: 42}, false},
+		{"invalid", "lsk2x1c0-x", "", eventID{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/events", nil)
+			if tt.header != "" {
+				r.Header.Set("Last-Event-ID", tt.header)
+			}
+			if tt.query != "" {
+				r.URL.RawQuery = "lastEventId=" + tt.query
+			}
+			got, err := lastEventID(r)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("lastEventID = %+v, %v; want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -2,30 +2,360 @@ package http
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/metrics"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/tracing"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/usecase"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var _ IFHTTPHandler = (*HTTPHandler)(nil)
 
+// logger is the logger of the http component
+var logger = logging.Logger("http")
+
+const (
+	// maxBodyBytes limits the size of request bodies
+	maxBodyBytes = 10 << 20
+	// shutdownTimeout bounds how long in-flight requests may take on shutdown
+	shutdownTimeout = 10 * time.Second
+)
+
 type IFHTTPHandler interface {
 	StartServer(ctx context.Context, port string) error
+	// SetAuth sets how callers are authenticated
+	SetAuth(cfg config.HTTPConfig) error
 }
 
 type HTTPHandler struct {
 	mcpUsecase    usecase.IFMCPUsecase
 	configUsecase usecase.IFConfigUsecase
+	approvals     *usecase.ApprovalUsecase
+	servers       *usecase.ServerPool
+	events        *eventBroker
+	authMu        sync.RWMutex
+	auth          *authenticator
 }
 
-func NewHTTPHandler(mcpUsecase *usecase.MCPUsecase, configUsecase *usecase.ConfigUsecase) *HTTPHandler {
+func NewHTTPHandler(mcpUsecase *usecase.MCPUsecase, configUsecase *usecase.ConfigUsecase, approvals *usecase.ApprovalUsecase, servers *usecase.ServerPool) *HTTPHandler {
 	return &HTTPHandler{
 		mcpUsecase:    mcpUsecase,
 		configUsecase: configUsecase,
+		approvals:     approvals,
+		servers:       servers,
+		events:        newEventBroker(eventBufferSize),
+		auth:          &authenticator{},
+	}
+}
+
+// ListenAddr returns the listen address of a bare port ("8080"), which is every
+// interface, or of a listen address ("127.0.0.1:8080")
+func ListenAddr(port string) string {
+	if !strings.Contains(port, ":") {
+		return ":" + port
+	}
+	return port
+}
+
+// IsLoopbackAddr reports whether a listen address only accepts local connections
+func IsLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(ListenAddr(addr))
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
 	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
 
+// StartServer serves the REST gateway until ctx is cancelled, then shuts down gracefully.
+// port may be a bare port ("8080") or a listen address ("127.0.0.1:8080").
 func (h *HTTPHandler) StartServer(ctx context.Context, port string) error {
-	log.Printf("HTTP server would start on port %s", port)
+	addr := ListenAddr(port)
+
+	// Forward notifications and connection changes to /events while serving
+	unsubscribeNotifications := h.mcpUsecase.SubscribeNotifications(h.events.publishMessage)
+	defer unsubscribeNotifications()
+	unsubscribeConnection := h.mcpUsecase.SubscribeConnectionState(h.events.publishConnection)
+	defer unsubscribeConnection()
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           h.Routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(_ net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info("HTTP server listening", "addr", addr)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("HTTP server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down HTTP server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down HTTP server: %w", err)
+	}
 	return nil
 }
+
+// Routes returns the REST gateway handler
+func (h *HTTPHandler) Routes() http.Handler {
+	mux := http.NewServeMux()
+	// The session routes are also served for every pooled server under /servers/{server}
+	for _, prefix := range []string{"", "/servers/{server}"} {
+		mux.HandleFunc("GET "+prefix+"/tools", h.handleListTools)
+		mux.HandleFunc("POST "+prefix+"/tools/{name}/call", h.handleCallTool)
+		mux.HandleFunc("GET "+prefix+"/resources", h.handleListResources)
+		mux.HandleFunc("GET "+prefix+"/resources/read", h.handleReadResource)
+		mux.HandleFunc("GET "+prefix+"/prompts", h.handleListPrompts)
+		mux.HandleFunc("POST "+prefix+"/prompts/{name}", h.handleGetPrompt)
+		mux.HandleFunc("GET "+prefix+"/status", h.handleStatus)
+	}
+	mux.HandleFunc("GET /approvals", h.handleListApprovals)
+	mux.HandleFunc("POST /approvals/{id}/approve", h.handleApprove)
+	mux.HandleFunc("POST /approvals/{id}/deny", h.handleDeny)
+	mux.HandleFunc("GET /events", h.handleEvents)
+	mux.HandleFunc("GET /openapi.json", h.handleOpenAPI)
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s %s", r.Method, r.URL.Path))
+	})
+	// traceRequests reads the route the mux sets on its request, so nothing may
+	// copy the request between them
+	return logRequests(h.identifyCaller(traceRequests(mux)))
+}
+
+// handleListTools serves GET /tools
+func (h *HTTPHandler) handleListTools(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	tools, err := session.GetAvailableTools(r.Context())
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tools": tools})
+}
+
+// handleCallTool serves POST /tools/{name}/call; the body is the arguments object
+func (h *HTTPHandler) handleCallTool(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	arguments := make(map[string]interface{})
+	if err := decodeBody(w, r, &arguments); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := session.ExecuteTool(r.Context(), entity.ToolCall{
+		Name:      r.PathValue("name"),
+		Arguments: arguments,
+	})
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleListResources serves GET /resources
+func (h *HTTPHandler) handleListResources(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	resources, err := session.GetAvailableResources(r.Context())
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"resources": resources})
+}
+
+// handleReadResource serves GET /resources/read?uri=
+func (h *HTTPHandler) handleReadResource(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	uri := r.URL.Query().Get("uri")
+	if uri == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing uri query parameter"))
+		return
+	}
+
+	contents, err := session.ReadResource(r.Context(), uri)
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"contents": contents})
+}
+
+// handleListPrompts serves GET /prompts
+func (h *HTTPHandler) handleListPrompts(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	prompts, err := session.GetAvailablePrompts(r.Context())
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"prompts": prompts})
+}
+
+// handleGetPrompt serves POST /prompts/{name}; the body is the arguments object
+func (h *HTTPHandler) handleGetPrompt(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	arguments := make(map[string]string)
+	if err := decodeBody(w, r, &arguments); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := session.GetPrompt(r.Context(), entity.PromptRequest{
+		Name:      r.PathValue("name"),
+		Arguments: arguments,
+	})
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleStatus serves GET /status
+func (h *HTTPHandler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.session(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, session.GetConnection(r.Context()))
+}
+
+// session returns the session a request is routed to: the primary session, or
+// the pooled session of the server named by /servers/{server}. It writes a 404
+// when that server has no open session.
+func (h *HTTPHandler) session(w http.ResponseWriter, r *http.Request) (usecase.IFMCPUsecase, bool) {
+	name := r.PathValue("server")
+	if name == "" {
+		return h.mcpUsecase, true
+	}
+	if h.servers != nil {
+		if session, ok := h.servers.Session(name); ok {
+			return session, true
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("no open session to server %q", name))
+	return nil, false
+}
+
+// decodeBody decodes an optional JSON request body into v
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	body := http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	dec := json.NewDecoder(body)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer to flush streams
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// traceRequests runs each request in a server span that continues the W3C trace
+// context of the caller, so the spans of the MCP requests it makes link up with it
+func traceRequests(next http.Handler) http.Handler {
+	tracer := otel.Tracer(tracing.InstrumentationName)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.request.method", r.Method), attribute.String("url.path", r.URL.Path)))
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		r = r.WithContext(ctx)
+		next.ServeHTTP(rec, r)
+
+		if r.Pattern != "" {
+			span.SetName(r.Pattern)
+			span.SetAttributes(attribute.String("http.route", r.Pattern))
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
+
+// logRequests logs each request with its status and duration. The request ID is
+// taken from the X-Request-Id header, or generated, and echoed in the response.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		requestID := r.Header.Get("X-Request-Id")
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set("X-Request-Id", requestID)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		logger.Info("request", "request_id", requestID, "method", r.Method, "path", r.URL.Path,
+			"status", rec.status, "duration", time.Since(start).Round(time.Microsecond))
+	})
+}
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// openAPIVersion is the OpenAPI version of the generated document
+const openAPIVersion = "3.1.0"
+
+// schema is a JSON Schema object; OpenAPI 3.1 accepts MCP schemas unchanged
+type schema = map[string]interface{}
+
+// OpenAPI document types, limited to the parts the gateway uses
+type (
+	openAPIDocument struct {
+		OpenAPI    string              `json:"openapi"`
+		Info       openAPIInfo         `json:"info"`
+		Paths      map[string]pathItem `json:"paths"`
+		Components openAPIComponents   `json:"components"`
+	}
+	openAPIInfo struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+	}
+	pathItem struct {
+		Post *operation `json:"post,omitempty"`
+	}
+	operation struct {
+		OperationID string              `json:"operationId"`
+		Summary     string              `json:"summary,omitempty"`
+		Description string              `json:"description,omitempty"`
+		Tags        []string            `json:"tags,omitempty"`
+		RequestBody *requestBody        `json:"requestBody,omitempty"`
+		Responses   map[string]response `json:"responses"`
+	}
+	requestBody struct {
+		Required bool                 `json:"required"`
+		Content  map[string]mediaType `json:"content"`
+	}
+	response struct {
+		Description string               `json:"description"`
+		Content     map[string]mediaType `json:"content,omitempty"`
+	}
+	mediaType struct {
+		Schema schema `json:"schema"`
+	}
+	openAPIComponents struct {
+		Schemas map[string]schema `json:"schemas"`
+	}
+)
+
+// operationIDPattern matches characters that are not allowed in generated operation IDs
+var operationIDPattern = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// handleOpenAPI serves GET /openapi.json, generated from the tools the server offers now
+func (h *HTTPHandler) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	tools, err := h.mcpUsecase.GetAvailableTools(r.Context())
+	if err != nil {
+		writeUsecaseError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, buildOpenAPI(h.mcpUsecase.GetConnection(r.Context()), tools))
+}
+
+// buildOpenAPI describes one POST /tools/{name}/call operation per tool
+func buildOpenAPI(conn entity.Connection, tools []entity.Tool) openAPIDocument {
+	info := openAPIInfo{
+		Title:       "MCP gateway",
+		Version:     "0.0.0",
+		Description: fmt.Sprintf("Tools of the MCP server at %s", conn.ServerURL),
+	}
+	if conn.ServerInfo != nil {
+		info.Title = conn.ServerInfo.Name + " MCP gateway"
+		info.Version = conn.ServerInfo.Version
+	}
+
+	paths := make(map[string]pathItem, len(tools))
+	for _, tool := range tools {
+		paths["/tools/"+url.PathEscape(tool.Name)+"/call"] = pathItem{Post: toolOperation(tool)}
+	}
+
+	return openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info:    info,
+		Paths:   paths,
+		Components: openAPIComponents{
+			Schemas: map[string]schema{
+				"Content":    contentSchema(),
+				"ToolResult": toolResultSchema(),
+				"Error":      errorSchema(),
+			},
+		},
+	}
+}
+
+// toolOperation maps the input schema of a tool to the request body and
+// its output schema, when present, to structuredContent of the response
+func toolOperation(tool entity.Tool) *operation {
+	input := tool.InputSchema
+	if input == nil {
+		input = schema{"type": "object"}
+	}
+	required, _ := input["required"].([]interface{})
+
+	result := schema{"$ref": "#/components/schemas/ToolResult"}
+	if tool.OutputSchema != nil {
+		result = schema{
+			"allOf": []interface{}{
+				result,
+				schema{"properties": schema{"structuredContent": tool.OutputSchema}},
+			},
+		}
+	}
+
+	op := &operation{
+		OperationID: operationID(tool.Name),
+		Summary:     firstLine(tool.Description),
+		Tags:        []string{"tools"},
+		RequestBody: &requestBody{
+			Required: len(required) > 0,
+			Content:  jsonContent(input),
+		},
+		Responses: map[string]response{
+			"200": {
+				Description: "Tool result; isError is true when the tool reports a failure",
+				Content:     jsonContent(result),
+			},
+			"403": {
+				Description: "The tool policy does not allow the call or it was not approved",
+				Content:     jsonContent(schema{"$ref": "#/components/schemas/Error"}),
+			},
+			"default": {
+				Description: "Gateway or MCP error",
+				Content:     jsonContent(schema{"$ref": "#/components/schemas/Error"}),
+			},
+		},
+	}
+	if op.Summary != strings.TrimSpace(tool.Description) {
+		op.Description = tool.Description
+	}
+	return op
+}
+
+// operationID derives a camel case operation ID such as callGetWeather from a tool name
+func operationID(name string) string {
+	var b strings.Builder
+	b.WriteString("call")
+	for _, part := range operationIDPattern.Split(name, -1) {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// jsonContent wraps a schema as an application/json media type
+func jsonContent(s schema) map[string]mediaType {
+	return map[string]mediaType{"application/json": {Schema: s}}
+}
+
+// firstLine returns the first line of a description for use as a summary
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+// contentSchema describes entity.Content
+func contentSchema() schema {
+	return schema{
+		"type":     "object",
+		"required": []string{"type"},
+		"properties": schema{
+			"type":     schema{"type": "string", "enum": []string{"text", "image", "audio", "resource", "resource_link"}},
+			"text":     schema{"type": "string"},
+			"data":     schema{"type": "string"},
+			"mimeType": schema{"type": "string"},
+			"resource": schema{"type": "object"},
+		},
+	}
+}
+
+// toolResultSchema describes entity.ToolResult
+func toolResultSchema() schema {
+	return schema{
+		"type":     "object",
+		"required": []string{"content", "isError"},
+		"properties": schema{
+			"content": schema{
+				"type":  "array",
+				"items": schema{"$ref": "#/components/schemas/Content"},
+			},
+			"structuredContent": schema{"type": "object"},
+			"isError":           schema{"type": "boolean"},
+		},
+	}
+}
+
+// errorSchema describes errorResponse
+func errorSchema() schema {
+	return schema{
+		"type":     "object",
+		"required": []string{"error"},
+		"properties": schema{
+			"error": schema{
+				"type":     "object",
+				"required": []string{"status", "message"},
+				"properties": schema{
+					"status":  schema{"type": "integer"},
+					"message": schema{"type": "string"},
+					"code":    schema{"type": "integer"},
+					"data":    schema{},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,7 @@
+package http
+
+import "github.com/google/wire"
+
+var HTTPSet = wire.NewSet(
+	NewHTTPHandler,
+)
@@ -2,12 +2,15 @@ package message
 
 import (
 	"context"
-	"log"
 
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/usecase"
 )
 
+// logger is the logger of the message component
+var logger = logging.Logger("message")
+
 // MessageHandler defines the interface for MCP message handling operations
 
 var _ IFMessageHandler = (*MessageHandler)(nil)
@@ -28,21 +31,21 @@ func NewMessageHandler() *MessageHandler {
 func (h *MessageHandler) RegisterHandlers(mcpUsecase *usecase.IFMCPUsecase) {
 	// Register handler for tools/list
 	(*mcpUsecase).RegisterHandler("tools/list", func(msg *entity.Message) error {
-		log.Println("Received tools/list request")
+		logger.Debug("received tools/list request", "request_id", msg.ID)
 		// Here you would implement the actual tools listing logic
 		return nil
 	})
 
 	// Register handler for tools/call
 	(*mcpUsecase).RegisterHandler("tools/call", func(msg *entity.Message) error {
-		log.Println("Received tools/call request")
+		logger.Debug("received tools/call request", "request_id", msg.ID)
 		// Here you would implement the actual tool calling logic
 		return nil
 	})
 
 	// Register handler for ping
 	(*mcpUsecase).RegisterHandler("ping", func(msg *entity.Message) error {
-		log.Println("Received ping, sending pong")
+		logger.Debug("received ping, sending pong", "request_id", msg.ID)
 		// Send pong response
 		pongMsg := &entity.Message{
 			ID:     msg.ID,
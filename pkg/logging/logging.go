@@ -0,0 +1,148 @@
+// Package logging configures the structured loggers of the client. Loggers
+// returned by Logger can be created before Configure runs; they always write
+// with the current level, format and output.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options selects how logs are written
+type Options struct {
+	// Level is debug, info, warning or error
+	Level string
+	// Format is text or json
+	Format string
+	// Output replaces the writer logs go to; nil keeps the current one, initially stderr
+	Output io.Writer
+	// Frames logs every JSON-RPC frame sent and received at debug level
+	Frames bool
+}
+
+var (
+	level  = new(slog.LevelVar)
+	base   atomic.Pointer[slog.Handler]
+	frames atomic.Bool
+
+	// mu serializes Configure and guards output
+	mu     sync.Mutex
+	output io.Writer = os.Stderr
+)
+
+func init() {
+	var h slog.Handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+	base.Store(&h)
+}
+
+// Configure applies the options to every logger, including the slog and log defaults
+func Configure(opts Options) error {
+	lvl, err := ParseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	out := output
+	if opts.Output != nil {
+		out = opts.Output
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", FormatText:
+		h = slog.NewTextHandler(out, handlerOpts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(out, handlerOpts)
+	default:
+		return fmt.Errorf("invalid log format %q (use text or json)", opts.Format)
+	}
+
+	output = out
+	level.Set(lvl)
+	base.Store(&h)
+	frames.Store(opts.Frames)
+	slog.SetDefault(Logger("main"))
+	return nil
+}
+
+// ParseLevel converts a configured log level; empty means info
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warning", "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level %q", s)
+	}
+}
+
+// Logger returns the logger of a component such as transport, usecase or cli
+func Logger(component string) *slog.Logger {
+	return slog.New(&dynamicHandler{wrap: func(h slog.Handler) slog.Handler { return h }}).With("component", component)
+}
+
+// Frames reports whether wire level frame logging is enabled
+func Frames() bool {
+	return frames.Load()
+}
+
+// dynamicHandler forwards records to the handler installed by the latest Configure
+type dynamicHandler struct {
+	// wrap reapplies the attributes and groups added with With and WithGroup
+	wrap func(slog.Handler) slog.Handler
+	// wrapped caches wrap applied to the current base handler until Configure replaces it
+	wrapped atomic.Pointer[wrappedHandler]
+}
+
+// wrappedHandler is a base handler with the attributes and groups of a dynamicHandler applied
+type wrappedHandler struct {
+	base    *slog.Handler
+	handler slog.Handler
+}
+
+// Enabled implements slog.Handler
+func (h *dynamicHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return l >= level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *dynamicHandler) Handle(ctx context.Context, r slog.Record) error {
+	b := base.Load()
+	w := h.wrapped.Load()
+	if w == nil || w.base != b {
+		w = &wrappedHandler{base: b, handler: h.wrap(*b)}
+		h.wrapped.Store(w)
+	}
+	return w.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *dynamicHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	wrap := h.wrap
+	return &dynamicHandler{wrap: func(b slog.Handler) slog.Handler { return wrap(b).WithAttrs(attrs) }}
+}
+
+// WithGroup implements slog.Handler
+func (h *dynamicHandler) WithGroup(name string) slog.Handler {
+	wrap := h.wrap
+	return &dynamicHandler{wrap: func(b slog.Handler) slog.Handler { return wrap(b).WithGroup(name) }}
+}
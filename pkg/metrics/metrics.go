@@ -0,0 +1,134 @@
+// Package metrics holds the Prometheus metrics of the client. The collectors are
+// registered on Registry, which Handler serves in the Prometheus text format.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+const namespace = "mcpclient"
+
+// Request outcomes
+const (
+	OutcomeSuccess   = "success"
+	OutcomeError     = "error"
+	OutcomeTimeout   = "timeout"
+	OutcomeCanceled  = "canceled"
+	OutcomeTransport = "transport_error"
+)
+
+// Registry holds every collector of the client along with the Go and process collectors
+var Registry = prometheus.NewRegistry()
+
+var (
+	// Requests counts requests sent to servers by method, server and outcome
+	Requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "Requests sent to MCP servers by method, server and outcome.",
+	}, []string{"method", "server", "outcome"})
+
+	// RequestDuration observes the time until a response arrives, by method
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests to MCP servers by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// ToolCallDuration observes the latency of tools/call by tool; tools the server did not list are labelled "unknown"
+	ToolCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "tool_call_duration_seconds",
+		Help:      "Latency of tool calls by tool.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"tool"})
+
+	// InFlight is the number of requests waiting for a response, by server
+	InFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "requests_in_flight",
+		Help:      "Requests waiting for a response by server.",
+	}, []string{"server"})
+
+	// Reconnects counts connections opened by a session that was connected before
+	Reconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconnects_total",
+		Help:      "Connections re-established by a session, by server.",
+	}, []string{"server"})
+
+	// Notifications counts notifications received from servers by method
+	Notifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "Notifications received from MCP servers by method and server.",
+	}, []string{"method", "server"})
+
+	// BytesSent and BytesReceived count the size of the frames exchanged with servers
+	BytesSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sent_bytes_total",
+		Help:      "Bytes of the frames sent to MCP servers.",
+	}, []string{"server"})
+	BytesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "received_bytes_total",
+		Help:      "Bytes of the frames received from MCP servers.",
+	}, []string{"server"})
+
+	// ConnectionStatus is 1 for the current status of the connection to each server and 0 otherwise
+	ConnectionStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "connection_status",
+		Help:      "Current connection status by server; 1 for the current status.",
+	}, []string{"server", "status"})
+)
+
+// connectionStatuses are the values the status label takes
+var connectionStatuses = []entity.ConnectionStatus{
+	entity.ConnectionStatusDisconnected,
+	entity.ConnectionStatusConnecting,
+	entity.ConnectionStatusConnected,
+	entity.ConnectionStatusError,
+}
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		Requests,
+		RequestDuration,
+		ToolCallDuration,
+		InFlight,
+		Reconnects,
+		Notifications,
+		BytesSent,
+		BytesReceived,
+		ConnectionStatus,
+	)
+}
+
+// SetConnectionStatus records the current status of the connection to a server
+func SetConnectionStatus(server string, status entity.ConnectionStatus) {
+	if server == "" {
+		return
+	}
+	for _, s := range connectionStatuses {
+		value := 0.0
+		if s == status {
+			value = 1
+		}
+		ConnectionStatus.WithLabelValues(server, string(s)).Set(value)
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,72 @@
+// Package tracing exports the spans of the client over OTLP. Until Configure
+// enables an exporter the global tracer provider is a no-op.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// InstrumentationName names the tracer the client creates its spans with
+const InstrumentationName = "github.com/t-yamakoshi/go-mcp-client"
+
+// DefaultServiceName is the service.name of exported spans
+const DefaultServiceName = "mcpclient"
+
+// Options selects where spans are exported
+type Options struct {
+	// Endpoint is an OTLP/HTTP endpoint such as http://localhost:4318. When empty,
+	// spans are exported only if OTEL_EXPORTER_OTLP_ENDPOINT or
+	// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set.
+	Endpoint string
+	// ServiceName defaults to DefaultServiceName
+	ServiceName string
+}
+
+// Configure installs an OTLP exporting tracer provider as the global one. The
+// returned function flushes pending spans and stops the exporter; it is a no-op
+// when tracing stays disabled.
+func Configure(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	if !Enabled(opts) {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporterOpts []otlptracehttp.Option
+	if opts.Endpoint != "" {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Enabled reports whether Configure exports spans with these options
+func Enabled(opts Options) bool {
+	return opts.Endpoint != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
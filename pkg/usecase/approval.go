@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
+)
+
+// DefaultApprovalTimeout is how long a tool call waits for approval unless configured otherwise
+const DefaultApprovalTimeout = time.Minute
+
+// ErrApprovalNotFound is returned by DecideApproval for a request that is not pending
+var ErrApprovalNotFound = errors.New("approval request not found")
+
+// ErrSelfApproval is returned by DecideApproval when the caller of a tool call tries to approve it
+var ErrSelfApproval = errors.New("a tool call cannot be approved by its own caller")
+
+// ErrUnauthenticatedCaller is returned by RequestApproval when a request would be
+// queued for a caller whose identity was not verified, since nobody could tell
+// whether its approver is someone else
+var ErrUnauthenticatedCaller = errors.New("a tool call that needs approval requires an authenticated caller")
+
+// ErrUnauthenticatedApprover is returned by DecideApproval when the identity of the caller was not verified
+var ErrUnauthenticatedApprover = errors.New("deciding an approval requires an authenticated caller")
+
+// ApprovalError is returned by ExecuteTool for a call that was not approved;
+// the call never reaches the server
+type ApprovalError struct {
+	Tool     string
+	Approval entity.Approval
+}
+
+// Error implements error
+func (e *ApprovalError) Error() string {
+	switch {
+	case e.Approval.Decision == entity.ApprovalTimedOut:
+		return fmt.Sprintf("tool %s was not approved in time", e.Tool)
+	case e.Approval.Approver != "":
+		return fmt.Sprintf("tool %s was denied by %s", e.Tool, e.Approval.Approver)
+	default:
+		return fmt.Sprintf("tool %s was denied", e.Tool)
+	}
+}
+
+// ApprovalPrompter asks a human whether a tool call may proceed. It should give
+// up once ctx is done.
+type ApprovalPrompter func(ctx context.Context, request entity.ApprovalRequest) (bool, error)
+
+// ApprovalUsecase pauses tool calls until a human approves them. With a
+// prompter the human is asked right away; without one requests wait in a
+// queue until DecideApproval is called.
+type ApprovalUsecase struct {
+	logger   *slog.Logger
+	mu       sync.Mutex
+	timeout  time.Duration
+	prompter ApprovalPrompter
+	pending  map[string]*pendingApproval
+}
+
+// pendingApproval is a queued request and the channel its decision is sent on
+type pendingApproval struct {
+	request entity.ApprovalRequest
+	decided chan entity.Approval
+}
+
+// NewApprovalUsecase creates an approval usecase that queues requests
+func NewApprovalUsecase() *ApprovalUsecase {
+	return &ApprovalUsecase{
+		logger:  logging.Logger("approval"),
+		timeout: DefaultApprovalTimeout,
+		pending: make(map[string]*pendingApproval),
+	}
+}
+
+// SetTimeout changes how long a request waits; zero restores the default
+func (uc *ApprovalUsecase) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultApprovalTimeout
+	}
+	uc.mu.Lock()
+	uc.timeout = timeout
+	uc.mu.Unlock()
+}
+
+// SetPrompter asks prompter for every approval; nil queues requests instead
+func (uc *ApprovalUsecase) SetPrompter(prompter ApprovalPrompter) {
+	uc.mu.Lock()
+	uc.prompter = prompter
+	uc.mu.Unlock()
+}
+
+// RequestApproval blocks until the request is approved, denied or times out.
+// It returns an ApprovalError unless the call was approved. Requests are only
+// queued for an authenticated caller of ctx.
+func (uc *ApprovalUsecase) RequestApproval(ctx context.Context, request entity.ApprovalRequest) (entity.Approval, error) {
+	uc.mu.Lock()
+	timeout, prompter := uc.timeout, uc.prompter
+	uc.mu.Unlock()
+	if prompter == nil && !CallerAuthenticated(ctx) {
+		return entity.Approval{}, fmt.Errorf("tool %s: %w", request.Tool, ErrUnauthenticatedCaller)
+	}
+
+	request.ID = uuid.New().String()
+	request.CreatedAt = time.Now().UTC()
+	request.ExpiresAt = request.CreatedAt.Add(timeout)
+	waitCtx, cancel := context.WithDeadline(ctx, request.ExpiresAt)
+	defer cancel()
+
+	var approval entity.Approval
+	if prompter != nil {
+		approved, err := prompter(waitCtx, request)
+		switch {
+		case ctx.Err() != nil:
+			return approval, fmt.Errorf("approval of tool %s: %w", request.Tool, ctx.Err())
+		case waitCtx.Err() != nil:
+			// An answer given after the deadline does not count
+			approval.Decision = entity.ApprovalTimedOut
+		case err != nil:
+			return approval, fmt.Errorf("failed to ask for approval of tool %s: %w", request.Tool, err)
+		case approved:
+			approval = entity.Approval{Decision: entity.ApprovalApproved, Approver: request.Caller}
+		default:
+			approval = entity.Approval{Decision: entity.ApprovalDenied, Approver: request.Caller}
+		}
+	} else {
+		var err error
+		if approval, err = uc.waitForDecision(ctx, waitCtx, request); err != nil {
+			return approval, err
+		}
+	}
+
+	uc.logger.Info("tool call approval", "id", request.ID, "tool", request.Tool, "decision", approval.Decision, "approver", approval.Approver)
+	if approval.Decision != entity.ApprovalApproved {
+		return approval, &ApprovalError{Tool: request.Tool, Approval: approval}
+	}
+	return approval, nil
+}
+
+// waitForDecision queues a request until DecideApproval answers it or waitCtx is done
+func (uc *ApprovalUsecase) waitForDecision(ctx, waitCtx context.Context, request entity.ApprovalRequest) (entity.Approval, error) {
+	pending := &pendingApproval{request: request, decided: make(chan entity.Approval, 1)}
+	uc.mu.Lock()
+	uc.pending[request.ID] = pending
+	uc.mu.Unlock()
+	uc.logger.Warn("tool call is waiting for approval", "id", request.ID, "tool", request.Tool, "server", request.Server, "caller", request.Caller)
+
+	select {
+	case approval := <-pending.decided:
+		return approval, nil
+	case <-waitCtx.Done():
+	}
+
+	uc.mu.Lock()
+	_, stillPending := uc.pending[request.ID]
+	delete(uc.pending, request.ID)
+	uc.mu.Unlock()
+	if !stillPending {
+		// Decided while the deadline passed
+		return <-pending.decided, nil
+	}
+	if ctx.Err() != nil {
+		return entity.Approval{}, fmt.Errorf("approval of tool %s: %w", request.Tool, ctx.Err())
+	}
+	return entity.Approval{Decision: entity.ApprovalTimedOut}, nil
+}
+
+// PendingApprovals returns the queued requests, oldest first
+func (uc *ApprovalUsecase) PendingApprovals(ctx context.Context) []entity.ApprovalRequest {
+	uc.mu.Lock()
+	requests := make([]entity.ApprovalRequest, 0, len(uc.pending))
+	for _, pending := range uc.pending {
+		requests = append(requests, pending.request)
+	}
+	uc.mu.Unlock()
+
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+	})
+	return requests
+}
+
+// DecideApproval approves or denies a queued request on behalf of the caller of
+// ctx, which must be authenticated. Whoever made the call may deny it but not approve it.
+func (uc *ApprovalUsecase) DecideApproval(ctx context.Context, id string, approved bool) (entity.Approval, error) {
+	if !CallerAuthenticated(ctx) {
+		return entity.Approval{}, ErrUnauthenticatedApprover
+	}
+	approval := entity.Approval{Decision: entity.ApprovalDenied, Approver: CallerFrom(ctx)}
+	if approved {
+		approval.Decision = entity.ApprovalApproved
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	pending, ok := uc.pending[id]
+	if !ok {
+		return entity.Approval{}, fmt.Errorf("%w: %s", ErrApprovalNotFound, id)
+	}
+	if approved && approval.Approver == pending.request.Caller {
+		return entity.Approval{}, ErrSelfApproval
+	}
+	delete(uc.pending, id)
+	pending.decided <- approval
+	return approval, nil
+}
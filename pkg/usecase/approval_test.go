@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// approvalResult is what RequestApproval returned
+type approvalResult struct {
+	approval entity.Approval
+	err      error
+}
+
+// queueApproval starts a request made by caller and returns its ID once it is pending
+func queueApproval(t *testing.T, uc *ApprovalUsecase, caller string) (string, <-chan approvalResult) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(WithAuthenticatedCaller(context.Background(), caller))
+	t.Cleanup(cancel)
+	done := make(chan approvalResult, 1)
+	go func() {
+		approval, err := uc.RequestApproval(ctx, entity.ApprovalRequest{Tool: "delete_file", Caller: caller})
+		done <- approvalResult{approval, err}
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if pending := uc.PendingApprovals(context.Background()); len(pending) == 1 {
+			return pending[0].ID, done
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("the request was not queued")
+	return "", nil
+}
+
+// waitResult returns the result of a request or fails after a second
+func waitResult(t *testing.T, done <-chan approvalResult) approvalResult {
+	t.Helper()
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(time.Second):
+		t.Fatal("RequestApproval did not return")
+		return approvalResult{}
+	}
+}
+
+func TestDecideApproval(t *testing.T) {
+	uc := NewApprovalUsecase()
+	id, done := queueApproval(t, uc, "http:alice")
+
+	approver := WithAuthenticatedCaller(context.Background(), "http:bob")
+	approval, err := uc.DecideApproval(approver, id, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approval.Decision != entity.ApprovalApproved || approval.Approver != "http:bob" {
+		t.Errorf("approval = %+v, want approved by http:bob", approval)
+	}
+	result := waitResult(t, done)
+	if result.err != nil || result.approval != approval {
+		t.Errorf("RequestApproval = %+v, %v; want %+v", result.approval, result.err, approval)
+	}
+
+	if _, err := uc.DecideApproval(approver, id, true); !errors.Is(err, ErrApprovalNotFound) {
+		t.Errorf("deciding twice = %v, want %v", err, ErrApprovalNotFound)
+	}
+}
+
+func TestDecideApprovalRejectsSelfApproval(t *testing.T) {
+	uc := NewApprovalUsecase()
+	id, done := queueApproval(t, uc, "http:alice")
+	self := WithAuthenticatedCaller(context.Background(), "http:alice")
+
+	if _, err := uc.DecideApproval(self, id, true); !errors.Is(err, ErrSelfApproval) {
+		t.Fatalf("self approval = %v, want %v", err, ErrSelfApproval)
+	}
+	if len(uc.PendingApprovals(context.Background())) != 1 {
+		t.Fatal("a rejected self approval decided the request")
+	}
+
+	// The caller may still withdraw the call
+	if _, err := uc.DecideApproval(self, id, false); err != nil {
+		t.Fatal(err)
+	}
+	result := waitResult(t, done)
+	var approvalErr *ApprovalError
+	if !errors.As(result.err, &approvalErr) || result.approval.Decision != entity.ApprovalDenied {
+		t.Errorf("RequestApproval = %+v, %v; want denied", result.approval, result.err)
+	}
+}
+
+func TestRequestApprovalRequiresAuthenticatedCaller(t *testing.T) {
+	uc := NewApprovalUsecase()
+
+	// An anonymous caller could otherwise approve its own call under a verified name
+	anonymous := WithCaller(context.Background(), "http:127.0.0.1")
+	_, err := uc.RequestApproval(anonymous, entity.ApprovalRequest{Tool: "delete_file", Caller: "http:127.0.0.1"})
+	if !errors.Is(err, ErrUnauthenticatedCaller) {
+		t.Fatalf("RequestApproval = %v, want %v", err, ErrUnauthenticatedCaller)
+	}
+	if pending := uc.PendingApprovals(context.Background()); len(pending) != 0 {
+		t.Errorf("%d requests of an unauthenticated caller were queued", len(pending))
+	}
+
+	// A prompter asks the local user, who needs no verification
+	uc.SetPrompter(func(ctx context.Context, request entity.ApprovalRequest) (bool, error) { return true, nil })
+	if _, err := uc.RequestApproval(anonymous, entity.ApprovalRequest{Tool: "delete_file"}); err != nil {
+		t.Errorf("RequestApproval with a prompter = %v, want approved", err)
+	}
+}
+
+func TestDecideApprovalRequiresAuthenticatedCaller(t *testing.T) {
+	uc := NewApprovalUsecase()
+	id, _ := queueApproval(t, uc, "http:alice")
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		WithCaller(context.Background(), "http:bob"),
+	} {
+		for _, approved := range []bool{true, false} {
+			if _, err := uc.DecideApproval(ctx, id, approved); !errors.Is(err, ErrUnauthenticatedApprover) {
+				t.Errorf("decide(%v) as %q = %v, want %v", approved, CallerFrom(ctx), err, ErrUnauthenticatedApprover)
+			}
+		}
+	}
+	if len(uc.PendingApprovals(context.Background())) != 1 {
+		t.Error("an unauthenticated caller decided the request")
+	}
+}
+
+func TestRequestApprovalTimesOut(t *testing.T) {
+	uc := NewApprovalUsecase()
+	uc.SetTimeout(20 * time.Millisecond)
+
+	ctx := WithAuthenticatedCaller(context.Background(), "http:alice")
+	approval, err := uc.RequestApproval(ctx, entity.ApprovalRequest{Tool: "delete_file"})
+	var approvalErr *ApprovalError
+	if !errors.As(err, &approvalErr) || approval.Decision != entity.ApprovalTimedOut {
+		t.Fatalf("RequestApproval = %+v, %v; want a timeout", approval, err)
+	}
+	if pending := uc.PendingApprovals(context.Background()); len(pending) != 0 {
+		t.Errorf("%d requests are still pending after the timeout", len(pending))
+	}
+}
+
+func TestRequestApprovalCanceled(t *testing.T) {
+	uc := NewApprovalUsecase()
+	ctx, cancel := context.WithCancel(WithAuthenticatedCaller(context.Background(), "http:alice"))
+	cancel()
+
+	_, err := uc.RequestApproval(ctx, entity.ApprovalRequest{Tool: "delete_file"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("RequestApproval = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRequestApprovalWithPrompter(t *testing.T) {
+	tests := []struct {
+		name     string
+		prompter ApprovalPrompter
+		want     entity.ApprovalDecision
+	}{
+		{
+			name:     "approved",
+			prompter: func(ctx context.Context, request entity.ApprovalRequest) (bool, error) { return true, nil },
+			want:     entity.ApprovalApproved,
+		},
+		{
+			name:     "denied",
+			prompter: func(ctx context.Context, request entity.ApprovalRequest) (bool, error) { return false, nil },
+			want:     entity.ApprovalDenied,
+		},
+		{
+			name: "answered after the deadline",
+			prompter: func(ctx context.Context, request entity.ApprovalRequest) (bool, error) {
+				<-ctx.Done()
+				return true, nil
+			},
+			want: entity.ApprovalTimedOut,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewApprovalUsecase()
+			uc.SetTimeout(20 * time.Millisecond)
+			uc.SetPrompter(tt.prompter)
+
+			approval, err := uc.RequestApproval(WithCaller(context.Background(), "cli:alice"), entity.ApprovalRequest{Tool: "delete_file", Caller: "cli:alice"})
+			if approval.Decision != tt.want {
+				t.Errorf("decision = %s, want %s", approval.Decision, tt.want)
+			}
+			if (err == nil) != (tt.want == entity.ApprovalApproved) {
+				t.Errorf("err = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
+)
+
+// redactedValue replaces sensitive argument values in audit records
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeys are parts of argument names whose values are always redacted;
+// names ending in "token" are redacted as well
+var sensitiveKeys = []string{"password", "passwd", "secret", "apikey", "api_key", "authorization", "credential", "private_key"}
+
+// AuditUsecase writes the audit log of tool calls. Once a record cannot be
+// written, tool calls are refused until a record is written again or the audit
+// log is restarted, so that no call goes unrecorded for long.
+type AuditUsecase struct {
+	auditRepo  repository.IFAuditRepository
+	mu         sync.RWMutex
+	redactKeys []string
+	// failure is the error of the last record that could not be written
+	failure error
+}
+
+// NewAuditUsecase creates the audit usecase; nothing is recorded until StartAudit
+func NewAuditUsecase(auditRepo repository.IFAuditRepository) *AuditUsecase {
+	return &AuditUsecase{auditRepo: auditRepo}
+}
+
+// StartAudit starts appending records to the audit log of cfg; an empty path stops auditing
+func (uc *AuditUsecase) StartAudit(ctx context.Context, cfg config.AuditConfig) error {
+	if err := uc.auditRepo.Open(cfg); err != nil {
+		return fmt.Errorf("failed to start the audit log: %w", err)
+	}
+	uc.mu.Lock()
+	uc.redactKeys = cfg.RedactKeys
+	uc.failure = nil
+	uc.mu.Unlock()
+	return nil
+}
+
+// CheckAudit returns an error while the last record could not be written
+func (uc *AuditUsecase) CheckAudit(ctx context.Context) error {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	if uc.failure != nil {
+		return fmt.Errorf("tool calls are refused until the audit log can be written: %w", uc.failure)
+	}
+	return nil
+}
+
+// StopAudit closes the audit log
+func (uc *AuditUsecase) StopAudit(ctx context.Context) error {
+	return uc.auditRepo.Close()
+}
+
+// RedactArguments returns a copy of tool call arguments with sensitive values
+// replaced, as they are written to the audit log
+func (uc *AuditUsecase) RedactArguments(arguments map[string]interface{}) map[string]interface{} {
+	uc.mu.RLock()
+	redactKeys := uc.redactKeys
+	uc.mu.RUnlock()
+	return redactArguments(arguments, redactKeys)
+}
+
+// RecordToolCall appends a record of a finished tool call with its arguments redacted
+func (uc *AuditUsecase) RecordToolCall(ctx context.Context, connection entity.Connection, toolCall entity.ToolCall, start time.Time, approval *entity.Approval, result *entity.ToolResult, callErr error) error {
+	record := &entity.AuditRecord{
+		Time:       start.UTC(),
+		Session:    connection.ID,
+		Server:     connection.ServerURL,
+		Tool:       toolCall.Name,
+		Arguments:  uc.RedactArguments(toolCall.Arguments),
+		Status:     entity.AuditStatusSuccess,
+		DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+		Caller:     CallerFrom(ctx),
+		Approval:   approval,
+	}
+	var policyErr *PolicyError
+	var approvalErr *ApprovalError
+	switch {
+	case errors.As(callErr, &policyErr):
+		record.Status = entity.AuditStatusDenied
+		record.Error = policyErr.Reason
+	case errors.As(callErr, &approvalErr):
+		record.Status = entity.AuditStatusDenied
+		record.Error = approvalErr.Error()
+	case callErr != nil:
+		record.Status = entity.AuditStatusError
+		record.Error = config.DefaultRedactor.Redact(callErr.Error())
+	case result != nil && result.IsError:
+		record.Status = entity.AuditStatusToolError
+	}
+
+	err := uc.auditRepo.Append(ctx, record)
+	uc.mu.Lock()
+	uc.failure = err
+	uc.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to write the audit log: %w", err)
+	}
+	return nil
+}
+
+// VerifyAudit checks the hash chain across files given oldest first and returns
+// the number of records checked
+func (uc *AuditUsecase) VerifyAudit(ctx context.Context, paths []string) (int, error) {
+	total := 0
+	prevHash := ""
+	for _, path := range paths {
+		lastHash, records, err := uc.auditRepo.Verify(ctx, path, prevHash)
+		total += records
+		if err != nil {
+			return total, err
+		}
+		if records > 0 {
+			prevHash = lastHash
+		}
+	}
+	return total, nil
+}
+
+// AuditFiles returns the files of an audit log, its rotations first
+func (uc *AuditUsecase) AuditFiles(ctx context.Context, path string) ([]string, error) {
+	return uc.auditRepo.Files(path)
+}
+
+// redactArguments copies tool arguments, replacing the values of sensitive keys
+// and known secrets in strings
+func redactArguments(arguments map[string]interface{}, redactKeys []string) map[string]interface{} {
+	if arguments == nil {
+		return nil
+	}
+	redacted, _ := redactValue(arguments, redactKeys).(map[string]interface{})
+	return redacted
+}
+
+// redactValue redacts one decoded JSON value
+func redactValue(value interface{}, redactKeys []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isSensitiveKey(key, redactKeys) {
+				redacted[key] = redactedValue
+				continue
+			}
+			redacted[key] = redactValue(item, redactKeys)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(item, redactKeys)
+		}
+		return redacted
+	case string:
+		return config.DefaultRedactor.Redact(v)
+	default:
+		return v
+	}
+}
+
+// isSensitiveKey reports whether the value of an argument must not be recorded
+func isSensitiveKey(key string, redactKeys []string) bool {
+	lower := strings.ToLower(key)
+	if strings.HasSuffix(lower, "token") {
+		return true
+	}
+	for _, part := range sensitiveKeys {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	for _, redactKey := range redactKeys {
+		if strings.EqualFold(key, redactKey) {
+			return true
+		}
+	}
+	return false
+}
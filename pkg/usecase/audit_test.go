@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// memoryAuditRepository keeps appended records in memory
+type memoryAuditRepository struct {
+	records []entity.AuditRecord
+	err     error
+}
+
+func (r *memoryAuditRepository) Open(cfg config.AuditConfig) error { return nil }
+
+func (r *memoryAuditRepository) Append(ctx context.Context, record *entity.AuditRecord) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.records = append(r.records, *record)
+	return nil
+}
+
+func (r *memoryAuditRepository) Close() error { return nil }
+
+func (r *memoryAuditRepository) Files(path string) ([]string, error) { return nil, nil }
+
+func (r *memoryAuditRepository) Verify(ctx context.Context, path, prevHash string) (string, int, error) {
+	return "", 0, nil
+}
+
+func TestRecordToolCallRedactsArguments(t *testing.T) {
+	config.DefaultRedactor.Add("resolved-secret-value")
+	repo := &memoryAuditRepository{}
+	uc := NewAuditUsecase(repo)
+	if err := uc.StartAudit(context.Background(), config.AuditConfig{Path: "audit.jsonl", RedactKeys: []string{"Customer_ID"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	arguments := map[string]interface{}{
+		"path":         "/srv/data/report.txt",
+		"password":     "hunter2",
+		"DB_Password":  "hunter2",
+		"github_token": "ghp_abc",
+		"tokenizer":    "bpe",
+		"customer_id":  "c-42",
+		"headers": map[string]interface{}{
+			"Authorization": "Bearer abc",
+			"Accept":        "application/json",
+		},
+		"items": []interface{}{
+			map[string]interface{}{"api_key": "k", "name": "first"},
+			"uses resolved-secret-value inline",
+		},
+		"count": 3.0,
+	}
+	call := entity.ToolCall{Name: "upload", Arguments: arguments}
+	if err := uc.RecordToolCall(context.Background(), entity.Connection{}, call, time.Now(), nil, &entity.ToolResult{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"path":         "/srv/data/report.txt",
+		"password":     redactedValue,
+		"DB_Password":  redactedValue,
+		"github_token": redactedValue,
+		"tokenizer":    "bpe",
+		"customer_id":  redactedValue,
+		"headers": map[string]interface{}{
+			"Authorization": redactedValue,
+			"Accept":        "application/json",
+		},
+		"items": []interface{}{
+			map[string]interface{}{"api_key": redactedValue, "name": "first"},
+			"uses [REDACTED] inline",
+		},
+		"count": 3.0,
+	}
+	if len(repo.records) != 1 {
+		t.Fatalf("got %d records, want 1", len(repo.records))
+	}
+	if got := repo.records[0].Arguments; !reflect.DeepEqual(got, want) {
+		t.Errorf("arguments = %v, want %v", got, want)
+	}
+	if arguments["password"] != "hunter2" {
+		t.Error("the arguments of the call were modified")
+	}
+}
+
+func TestRecordToolCallStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *entity.ToolResult
+		err    error
+		want   entity.AuditStatus
+	}{
+		{"success", &entity.ToolResult{}, nil, entity.AuditStatusSuccess},
+		{"tool error", &entity.ToolResult{IsError: true}, nil, entity.AuditStatusToolError},
+		{"error", nil, errors.New("connection lost"), entity.AuditStatusError},
+		{"policy", nil, &PolicyError{Tool: "rm", Reason: "denied by rule 0"}, entity.AuditStatusDenied},
+		{"approval", nil, &ApprovalError{Tool: "rm", Approval: entity.Approval{Decision: entity.ApprovalTimedOut}}, entity.AuditStatusDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryAuditRepository{}
+			uc := NewAuditUsecase(repo)
+			if err := uc.RecordToolCall(context.Background(), entity.Connection{}, entity.ToolCall{Name: "rm"}, time.Now(), nil, tt.result, tt.err); err != nil {
+				t.Fatal(err)
+			}
+			if got := repo.records[0].Status; got != tt.want {
+				t.Errorf("status = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAuditAfterWriteFailure(t *testing.T) {
+	repo := &memoryAuditRepository{err: errors.New("no space left on device")}
+	uc := NewAuditUsecase(repo)
+	ctx := context.Background()
+	if err := uc.CheckAudit(ctx); err != nil {
+		t.Fatalf("check before any record = %v", err)
+	}
+
+	if err := uc.RecordToolCall(ctx, entity.Connection{}, entity.ToolCall{Name: "echo"}, time.Now(), nil, nil, nil); err == nil {
+		t.Fatal("a failed write was not reported")
+	}
+	if err := uc.CheckAudit(ctx); err == nil {
+		t.Error("tool calls are allowed after a record could not be written")
+	}
+
+	repo.err = nil
+	if err := uc.RecordToolCall(ctx, entity.Connection{}, entity.ToolCall{Name: "echo"}, time.Now(), nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.CheckAudit(ctx); err != nil {
+		t.Errorf("check after a record was written again = %v", err)
+	}
+}
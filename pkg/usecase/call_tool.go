@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// ToolCallError is returned by CallTool when the tool reports a failure with isError
+type ToolCallError struct {
+	Tool   string
+	Result *entity.ToolResult
+}
+
+// Error implements error
+func (e *ToolCallError) Error() string {
+	if text := textContent(e.Result); text != "" {
+		return fmt.Sprintf("tool %s failed: %s", e.Tool, text)
+	}
+	return fmt.Sprintf("tool %s failed", e.Tool)
+}
+
+// CallTool calls a tool with typed arguments and decodes its result.
+// args is marshaled to JSON and validated against the tool's input schema before
+// the call. The result is decoded from structuredContent, or from the text content
+// as JSON when the tool returns none; a string Result receives the text as is.
+func CallTool[Args, Result any](ctx context.Context, session IFMCPUsecase, name string, args Args) (Result, error) {
+	var out Result
+
+	arguments, err := toArguments(args)
+	if err != nil {
+		return out, fmt.Errorf("failed to convert arguments for tool %s: %w", name, err)
+	}
+
+	tool, err := session.GetTool(ctx, name)
+	if err != nil {
+		return out, err
+	}
+	if problems := validateSchema(tool.InputSchema, arguments, "arguments"); len(problems) > 0 {
+		return out, &ValidationError{Tool: name, Problems: problems}
+	}
+
+	result, err := session.ExecuteTool(ctx, entity.ToolCall{Name: name, Arguments: arguments})
+	if err != nil {
+		return out, err
+	}
+	if result.IsError {
+		return out, &ToolCallError{Tool: name, Result: result}
+	}
+
+	if err := decodeToolResult(result, &out); err != nil {
+		return out, fmt.Errorf("failed to decode result of tool %s: %w", name, err)
+	}
+	return out, nil
+}
+
+// toArguments converts a Go value to the arguments object of a tool call
+func toArguments(args interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make(map[string]interface{})
+	if string(data) == "null" {
+		return arguments, nil
+	}
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		return nil, fmt.Errorf("arguments must be a JSON object: %w", err)
+	}
+	return arguments, nil
+}
+
+// decodeToolResult decodes structuredContent or the text content of a result into out
+func decodeToolResult(result *entity.ToolResult, out interface{}) error {
+	if s, ok := out.(*string); ok {
+		*s = textContent(result)
+		return nil
+	}
+
+	if result.StructuredContent != nil {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, out)
+	}
+
+	text := textContent(result)
+	if text == "" {
+		return fmt.Errorf("result has neither structured nor text content")
+	}
+	return json.Unmarshal([]byte(text), out)
+}
+
+// textContent joins the text items of a result
+func textContent(result *entity.ToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if content.Type == "text" {
+			texts = append(texts, content.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import "context"
+
+// callerKey is the context key of the caller identity
+type callerKey struct{}
+
+// WithCaller returns a context that identifies who makes the calls made with it,
+// such as cli:alice or http:bob; the identity is written to the audit log
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFrom returns the caller identity of a context, or "" when it has none
+func CallerFrom(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// authenticatedKey is the context key marking the caller identity as verified
+type authenticatedKey struct{}
+
+// WithAuthenticatedCaller is WithCaller for a caller whose identity was verified,
+// such as by a password; only such callers may decide approvals
+func WithAuthenticatedCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(WithCaller(ctx, caller), authenticatedKey{}, true)
+}
+
+// CallerAuthenticated reports whether the caller identity of a context was verified
+func CallerAuthenticated(ctx context.Context) bool {
+	authenticated, _ := ctx.Value(authenticatedKey{}).(bool)
+	return authenticated
+}
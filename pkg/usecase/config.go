@@ -2,14 +2,19 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 
 	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
-	"github.com/t-yamakoshi/go-mcp-client/pkg/infrastructure"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
 )
 
 var _ IFConfigUsecase = (*ConfigUsecase)(nil)
@@ -18,17 +23,25 @@ type IFConfigUsecase interface {
 	LoadConfiguration(ctx context.Context, configPath string) (*config.Config, error)
 	SaveConfiguration(ctx context.Context, config *config.Config, configPath string) error
 	ValidateConfiguration(ctx context.Context, config *config.Config) error
-	UpdateConfiguration(ctx context.Context, config *config.Config, updates map[string]interface{}) error
+	UpdateConfiguration(ctx context.Context, config *config.Config, updates ...config.Update) error
+	GetConfigurationValue(ctx context.Context, config *config.Config, path string) (interface{}, error)
 	GetDefaultConfiguration(ctx context.Context) *config.Config
+	ResolveConfiguration(ctx context.Context, configPath string, overrides []config.Override) (*config.Resolved, error)
+	ImportServers(ctx context.Context, cfg *config.Config, sourcePath string, overwrite bool) ([]string, error)
+	WatchConfiguration(ctx context.Context, configPath string, overrides []config.Override, onReload func(*config.Resolved)) error
 }
 
 type ConfigUsecase struct {
 	configRepo repository.IFConfigRepository
+	secretRepo repository.IFSecretRepository
+	logger     *slog.Logger
 }
 
-func NewConfigUsecase(configRepo *infrastructure.ConfigRepositoryImpl) *ConfigUsecase {
+func NewConfigUsecase(configRepo repository.IFConfigRepository, secretRepo repository.IFSecretRepository) *ConfigUsecase {
 	return &ConfigUsecase{
 		configRepo: configRepo,
+		secretRepo: secretRepo,
+		logger:     logging.Logger("config"),
 	}
 }
 
@@ -37,7 +50,7 @@ func (uc *ConfigUsecase) LoadConfiguration(ctx context.Context, configPath strin
 	// Check if file exists
 	if _, err := os.Stat(configPath); err == nil {
 		// File exists, load it
-		config, err := uc.configRepo.Load(ctx)
+		config, err := uc.configRepo.Load(ctx, configPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load config from %s: %w", configPath, err)
 		}
@@ -55,10 +68,16 @@ func (uc *ConfigUsecase) LoadConfiguration(ctx context.Context, configPath strin
 	return defaultConfig, nil
 }
 
-// SaveConfiguration saves configuration to file
-func (uc *ConfigUsecase) SaveConfiguration(ctx context.Context, config *config.Config, configPath string) error {
+// SaveConfiguration saves configuration to file. A configuration whose secret
+// references were expanded by ResolveConfiguration is refused, so secret values
+// never reach the file.
+func (uc *ConfigUsecase) SaveConfiguration(ctx context.Context, cfg *config.Config, configPath string) error {
+	if cfg != nil && config.DefaultRedactor.ContainsConfig(cfg) {
+		return fmt.Errorf("refusing to save a configuration with resolved secrets; save the configuration returned by LoadConfiguration")
+	}
+
 	// Validate configuration before saving
-	if err := uc.ValidateConfiguration(ctx, config); err != nil {
+	if err := uc.ValidateConfiguration(ctx, cfg); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
@@ -68,7 +87,146 @@ func (uc *ConfigUsecase) SaveConfiguration(ctx context.Context, config *config.C
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	return uc.configRepo.Save(ctx, config)
+	return uc.configRepo.Save(ctx, cfg, configPath)
+}
+
+// ResolveConfiguration layers defaults, the config file, MCPCLIENT_* environment
+// variables and command line overrides, in increasing precedence, expands secret
+// references and validates the result. A missing config file is not an error.
+// Resolved secret values are registered with config.DefaultRedactor.
+func (uc *ConfigUsecase) ResolveConfiguration(ctx context.Context, configPath string, overrides []config.Override) (*config.Resolved, error) {
+	cfg := uc.GetDefaultConfiguration(ctx)
+	resolved := &config.Resolved{
+		Config:  cfg,
+		Sources: make(map[string]config.Source),
+	}
+	keys := config.Keys()
+	for _, key := range append(config.CompositeKeys(), keys...) {
+		if !cfg.IsZero(key) {
+			resolved.Sources[key] = config.Source{Layer: config.LayerDefault}
+		}
+	}
+
+	// Values present in the file replace the defaults
+	if _, err := os.Stat(configPath); err == nil {
+		fileConfig, err := uc.configRepo.Load(ctx, configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config from %s: %w", configPath, err)
+		}
+		// The file is checked on its own first so unknown keys, which decoding drops, are reported
+		document, err := uc.configRepo.LoadDocument(ctx, configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config from %s: %w", configPath, err)
+		}
+		if problems := validateSchema(configSchema(), document, ""); len(problems) > 0 {
+			problems = append(problems, checkConfig(fileConfig)...)
+			return nil, fmt.Errorf("invalid configuration: %w", &ConfigError{Source: configPath, Problems: problems})
+		}
+		merged, err := cfg.Merge(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config from %s: %w", configPath, err)
+		}
+		for _, key := range merged {
+			resolved.Sources[key] = config.Source{Layer: config.LayerFile, Name: configPath}
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to access config file %s: %w", configPath, err)
+	}
+
+	// The profile sits between the file and the environment, but may be selected by either
+	if name := selectedProfile(cfg.Profile, overrides); name != "" {
+		profile, ok := cfg.Profiles[name]
+		if !ok {
+			err := fmt.Errorf("profile %q is not defined in %s", name, configPath)
+			if suggestion := suggest(name, cfg.ProfileNames()); suggestion != "" {
+				err = fmt.Errorf("%w, did you mean %q?", err, suggestion)
+			}
+			return nil, err
+		}
+		for _, key := range cfg.ApplyProfile(profile) {
+			resolved.Sources[key] = config.Source{Layer: config.LayerProfile, Name: name}
+		}
+	}
+
+	for _, key := range keys {
+		name := config.EnvVar(key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := cfg.Set(key, value); err != nil {
+			return nil, fmt.Errorf("environment variable %s: %w", name, err)
+		}
+		resolved.Sources[key] = config.Source{Layer: config.LayerEnv, Name: name}
+	}
+
+	for _, override := range overrides {
+		if err := cfg.Set(override.Key, override.Value); err != nil {
+			return nil, fmt.Errorf("flag -%s: %w", override.Flag, err)
+		}
+		resolved.Sources[override.Key] = config.Source{Layer: config.LayerFlag, Name: "-" + override.Flag}
+	}
+
+	// Secret references are expanded last so every layer can use them
+	expanded, secrets, err := cfg.ExpandSecrets(func(kind, reference string) (string, error) {
+		return uc.secretRepo.Resolve(ctx, kind, reference)
+	})
+	if err != nil {
+		return nil, err
+	}
+	config.DefaultRedactor.Add(secrets...)
+	resolved.Config = expanded
+
+	if err := uc.ValidateConfiguration(ctx, expanded); err != nil {
+		var configErr *ConfigError
+		if errors.As(err, &configErr) {
+			for i, problem := range configErr.Problems {
+				configErr.Problems[i] = config.DefaultRedactor.Redact(problem)
+			}
+		}
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return resolved, nil
+}
+
+// WatchConfiguration resolves the configuration again whenever the config file
+// changes and passes it to onReload until ctx is done. Changes that fail to load
+// or validate are logged and dropped, so the previous configuration stays active.
+func (uc *ConfigUsecase) WatchConfiguration(ctx context.Context, configPath string, overrides []config.Override, onReload func(*config.Resolved)) error {
+	current, err := uc.ResolveConfiguration(ctx, configPath, overrides)
+	if err != nil {
+		return err
+	}
+
+	return uc.configRepo.Watch(ctx, configPath, func() {
+		resolved, err := uc.ResolveConfiguration(ctx, configPath, overrides)
+		if err != nil {
+			uc.logger.Error("rejected configuration change, keeping the previous configuration", "path", configPath, "error", err)
+			return
+		}
+		if reflect.DeepEqual(resolved.Config, current.Config) {
+			return
+		}
+
+		uc.logger.Info("reloaded configuration", "path", configPath)
+		current = resolved
+		onReload(resolved)
+	})
+}
+
+// selectedProfile returns the profile named by the highest layer: a flag,
+// MCPCLIENT_PROFILE or the config file
+func selectedProfile(fileProfile string, overrides []config.Override) string {
+	name := fileProfile
+	if value, ok := os.LookupEnv(config.EnvVar("profile")); ok {
+		name = value
+	}
+	for _, override := range overrides {
+		if override.Key == "profile" {
+			name = override.Value
+		}
+	}
+	return name
 }
 
 // GetDefaultConfiguration returns the default configuration
@@ -83,62 +241,95 @@ func (uc *ConfigUsecase) GetDefaultConfiguration(ctx context.Context) *config.Co
 	}
 }
 
-// ValidateConfiguration validates the configuration
+// ValidateConfiguration checks the configuration against the config file schema and
+// checks URLs, timeouts and server definitions. Every problem is reported in a *ConfigError.
 func (uc *ConfigUsecase) ValidateConfiguration(ctx context.Context, config *config.Config) error {
 	if config == nil {
 		return fmt.Errorf("configuration cannot be nil")
 	}
 
-	if config.ServerURL == "" {
-		return fmt.Errorf("server URL cannot be empty")
+	document, err := toDocument(config)
+	if err != nil {
+		return err
 	}
 
-	if config.ClientInfo.Name == "" {
-		return fmt.Errorf("client name cannot be empty")
+	problems := validateSchema(configSchema(), document, "")
+	problems = append(problems, checkConfig(config)...)
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
 	}
+	return nil
+}
 
-	if config.ClientInfo.Version == "" {
-		return fmt.Errorf("client version cannot be empty")
+// ImportServers adds the servers defined in another MCP client's configuration file
+// to cfg and returns the names added. Existing servers are kept unless overwrite is set.
+func (uc *ConfigUsecase) ImportServers(ctx context.Context, cfg *config.Config, sourcePath string, overwrite bool) ([]string, error) {
+	servers, err := uc.configRepo.ImportServers(ctx, sourcePath)
+	if err != nil {
+		return nil, err
 	}
 
-	// Validate log level
-	validLogLevels := map[string]bool{
-		"debug":   true,
-		"info":    true,
-		"warning": true,
-		"error":   true,
+	if cfg.MCPServers == nil {
+		cfg.MCPServers = make(map[string]config.ServerConfig, len(servers))
 	}
 
-	if !validLogLevels[config.LogLevel] {
-		return fmt.Errorf("invalid log level: %s", config.LogLevel)
+	var added []string
+	for name, server := range servers {
+		if _, exists := cfg.MCPServers[name]; exists && !overwrite {
+			uc.logger.Info("skipping server: already defined", "server", name)
+			continue
+		}
+		if problems := checkServer("mcpServers."+name, server); len(problems) > 0 {
+			return nil, &ConfigError{Source: sourcePath, Problems: problems}
+		}
+		cfg.MCPServers[name] = server
+		added = append(added, name)
 	}
-
-	return nil
+	sort.Strings(added)
+	return added, nil
 }
 
-// UpdateConfiguration updates specific configuration fields
-func (uc *ConfigUsecase) UpdateConfiguration(ctx context.Context, config *config.Config, updates map[string]interface{}) error {
-	if config == nil {
+// UpdateConfiguration applies path based updates in order and validates the result.
+// Unknown paths and values that do not convert to the schema type are errors,
+// and cfg is left unchanged when any update fails.
+func (uc *ConfigUsecase) UpdateConfiguration(ctx context.Context, cfg *config.Config, updates ...config.Update) error {
+	if cfg == nil {
 		return fmt.Errorf("configuration cannot be nil")
 	}
 
-	// Apply updates
-	if serverURL, ok := updates["server_url"].(string); ok {
-		config.ServerURL = serverURL
+	document, err := toDocument(cfg)
+	if err != nil {
+		return err
 	}
 
-	if clientName, ok := updates["client_name"].(string); ok {
-		config.ClientInfo.Name = clientName
+	for _, update := range updates {
+		if update.Unset {
+			err = unsetPath(document, update.Path)
+		} else {
+			err = setPath(document, update.Path, update.Value)
+		}
+		if err != nil {
+			return err
+		}
 	}
 
-	if clientVersion, ok := updates["client_version"].(string); ok {
-		config.ClientInfo.Version = clientVersion
+	updated, err := fromDocument(document)
+	if err != nil {
+		return err
 	}
-
-	if logLevel, ok := updates["log_level"].(string); ok {
-		config.LogLevel = logLevel
+	if err := uc.ValidateConfiguration(ctx, updated); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	// Validate the updated configuration
-	return uc.ValidateConfiguration(ctx, config)
+	*cfg = *updated
+	return nil
+}
+
+// GetConfigurationValue returns the value at a dotted path of the configuration
+func (uc *ConfigUsecase) GetConfigurationValue(ctx context.Context, cfg *config.Config, path string) (interface{}, error) {
+	document, err := toDocument(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return getPath(document, path)
 }
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+)
+
+// splitPath splits a dotted configuration path; "servers" is accepted for mcpServers
+func splitPath(path string) ([]string, error) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("invalid configuration path %q", path)
+		}
+	}
+	if parts[0] == "servers" {
+		parts[0] = "mcpServers"
+	}
+	return parts, nil
+}
+
+// schemaAt returns the schema of every segment of a path, reporting unknown keys
+func schemaAt(parts []string) ([]map[string]interface{}, error) {
+	schemas := make([]map[string]interface{}, len(parts))
+	current := configSchema()
+	for i, part := range parts {
+		properties, _ := current["properties"].(map[string]interface{})
+		if child, ok := properties[part].(map[string]interface{}); ok {
+			current = child
+		} else if additional, ok := current["additionalProperties"].(map[string]interface{}); ok {
+			current = additional
+		} else {
+			return nil, unknownKeyError(parts[:i+1], properties)
+		}
+		schemas[i] = current
+	}
+	return schemas, nil
+}
+
+// unknownKeyError reports a path that the schema does not define
+func unknownKeyError(parts []string, properties map[string]interface{}) error {
+	path := strings.Join(parts, ".")
+	if len(properties) == 0 {
+		return fmt.Errorf("unknown configuration key %q: %s has no fields", path, strings.Join(parts[:len(parts)-1], "."))
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if suggestion := suggest(parts[len(parts)-1], names); suggestion != "" {
+		return fmt.Errorf("unknown configuration key %q, did you mean %q?", path, strings.Join(append(parts[:len(parts)-1:len(parts)-1], suggestion), "."))
+	}
+	return fmt.Errorf("unknown configuration key %q (valid keys here: %s)", path, strings.Join(names, ", "))
+}
+
+// getPath returns the value at a path of a configuration document
+func getPath(document map[string]interface{}, path string) (interface{}, error) {
+	parts, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := schemaAt(parts); err != nil {
+		return nil, err
+	}
+
+	var current interface{} = document
+	for _, part := range parts {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not set", path)
+		}
+		if current, ok = object[part]; !ok {
+			return nil, fmt.Errorf("%s is not set", path)
+		}
+	}
+	return current, nil
+}
+
+// setPath stores a value at a path, creating the objects on the way
+func setPath(document map[string]interface{}, path string, value interface{}) error {
+	parts, err := splitPath(path)
+	if err != nil {
+		return err
+	}
+	schemas, err := schemaAt(parts)
+	if err != nil {
+		return err
+	}
+
+	coerced, err := coerceValue(schemas[len(schemas)-1], value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	parent, err := parentObject(document, parts, true)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	parent[parts[len(parts)-1]] = coerced
+	return nil
+}
+
+// unsetPath removes the value at a path; removing a value that is not set is not an error
+func unsetPath(document map[string]interface{}, path string) error {
+	parts, err := splitPath(path)
+	if err != nil {
+		return err
+	}
+	if _, err := schemaAt(parts); err != nil {
+		return err
+	}
+
+	parent, err := parentObject(document, parts, false)
+	if err != nil || parent == nil {
+		return err
+	}
+	delete(parent, parts[len(parts)-1])
+	return nil
+}
+
+// parentObject walks to the object that holds the last segment of a path.
+// With create, missing objects are added; otherwise nil is returned for them.
+func parentObject(document map[string]interface{}, parts []string, create bool) (map[string]interface{}, error) {
+	current := document
+	for i, part := range parts[:len(parts)-1] {
+		next, ok := current[part]
+		if !ok || next == nil {
+			if !create {
+				return nil, nil
+			}
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		object, ok := next.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is a %s, not an object", strings.Join(parts[:i+1], "."), jsonType(next))
+		}
+		current = object
+	}
+	return current, nil
+}
+
+// coerceValue converts a string to the type a schema expects: booleans, numbers,
+// JSON or comma separated arrays, and JSON objects. Other values are returned unchanged.
+func coerceValue(schema map[string]interface{}, value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	types := schemaTypes(schema)
+	if len(types) == 0 || containsString(types, "string") {
+		return s, nil
+	}
+
+	switch types[0] {
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean (use true or false)", s)
+		}
+		return b, nil
+	case "integer":
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", s)
+		}
+		return float64(n), nil
+	case "number":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return f, nil
+	case "array":
+		if strings.HasPrefix(strings.TrimSpace(s), "[") {
+			var items []interface{}
+			if err := json.Unmarshal([]byte(s), &items); err != nil {
+				return nil, fmt.Errorf("invalid JSON array: %w", err)
+			}
+			return items, nil
+		}
+		items := []interface{}{}
+		if s != "" {
+			for _, item := range strings.Split(s, ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+		}
+		return items, nil
+	case "object":
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(s), &object); err != nil {
+			return nil, fmt.Errorf("expected a JSON object: %w", err)
+		}
+		return object, nil
+	}
+	return s, nil
+}
+
+// fromDocument decodes a configuration document, rejecting values of the wrong type
+func fromDocument(document map[string]interface{}) (*config.Config, error) {
+	if problems := validateSchema(configSchema(), document, ""); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration: %w", &ConfigError{Problems: problems})
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var cfg config.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+)
+
+// ConfigError lists every problem found in a configuration
+type ConfigError struct {
+	// Source is the file the problems were found in, empty for the effective configuration
+	Source   string
+	Problems []string
+}
+
+// Error implements error
+func (e *ConfigError) Error() string {
+	prefix := ""
+	if e.Source != "" {
+		prefix = e.Source + ": "
+	}
+	if len(e.Problems) == 1 {
+		return prefix + e.Problems[0]
+	}
+	return fmt.Sprintf("%s%d problems:\n  - %s", prefix, len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// configSchema is the decoded config file schema with its $defs references inlined
+var configSchema = sync.OnceValue(func() map[string]interface{} {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(config.Schema(), &schema); err != nil {
+		panic(fmt.Sprintf("invalid embedded config schema: %v", err))
+	}
+	defs, _ := schema["$defs"].(map[string]interface{})
+	return inlineRefs(schema, defs).(map[string]interface{})
+})
+
+// inlineRefs replaces "#/$defs/NAME" references with the definitions they name,
+// since the schema validator does not follow references. The config schema has no recursive definitions.
+func inlineRefs(node interface{}, defs map[string]interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			if def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")]; ok {
+				return inlineRefs(def, defs)
+			}
+		}
+		inlined := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			inlined[key] = inlineRefs(value, defs)
+		}
+		return inlined
+	case []interface{}:
+		inlined := make([]interface{}, len(v))
+		for i, value := range v {
+			inlined[i] = inlineRefs(value, defs)
+		}
+		return inlined
+	default:
+		return node
+	}
+}
+
+// toDocument converts a configuration to the generic form the schema validator reads
+func toDocument(cfg *config.Config) (map[string]interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return document, nil
+}
+
+// checkConfig checks the values the schema cannot express
+func checkConfig(cfg *config.Config) []string {
+	var problems []string
+	if cfg.ServerURL != "" {
+		problems = append(problems, checkURL("server_url", cfg.ServerURL, "ws", "wss", "replay")...)
+	}
+	if cfg.OTLPEndpoint != "" {
+		problems = append(problems, checkURL("otlp_endpoint", cfg.OTLPEndpoint, "http", "https")...)
+	}
+	problems = append(problems, checkDuration("dial_timeout", cfg.DialTimeout)...)
+	problems = append(problems, checkDuration("request_timeout", cfg.RequestTimeout)...)
+	problems = append(problems, checkDuration("approval_timeout", cfg.ApprovalTimeout)...)
+
+	for _, name := range cfg.ServerNames() {
+		problems = append(problems, checkServer("mcpServers."+name, cfg.MCPServers[name])...)
+	}
+	_, policyProblems := compilePolicy(cfg.Policy, "policy")
+	problems = append(problems, policyProblems...)
+	problems = append(problems, checkHTTP(cfg.HTTP)...)
+
+	if cfg.Profile != "" {
+		if _, ok := cfg.Profiles[cfg.Profile]; !ok {
+			problem := fmt.Sprintf("profile: %q is not defined in profiles", cfg.Profile)
+			if suggestion := suggest(cfg.Profile, cfg.ProfileNames()); suggestion != "" {
+				problem += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			problems = append(problems, problem)
+		}
+	}
+	for _, name := range cfg.ProfileNames() {
+		profile := cfg.Profiles[name]
+		path := "profiles." + name
+		if profile.ServerURL != "" {
+			problems = append(problems, checkURL(path+".server_url", profile.ServerURL, "ws", "wss", "replay")...)
+		}
+		problems = append(problems, checkDuration(path+".dial_timeout", profile.DialTimeout)...)
+		problems = append(problems, checkDuration(path+".request_timeout", profile.RequestTimeout)...)
+	}
+
+	if cfg.DefaultServer != "" {
+		server, ok := cfg.MCPServers[cfg.DefaultServer]
+		switch {
+		case !ok:
+			problem := fmt.Sprintf("default_server: %q is not defined in mcpServers", cfg.DefaultServer)
+			if suggestion := suggest(cfg.DefaultServer, cfg.ServerNames()); suggestion != "" {
+				problem += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			problems = append(problems, problem)
+		case server.Disabled:
+			problems = append(problems, fmt.Sprintf("default_server: server %q is disabled", cfg.DefaultServer))
+		}
+	}
+	return problems
+}
+
+// checkServer checks that a server definition names a command or a URL that fits its transport
+func checkServer(path string, server config.ServerConfig) []string {
+	switch transport := server.TransportType(); transport {
+	case config.TransportStdio:
+		if server.Command == "" {
+			return []string{path + ".command: required for stdio servers"}
+		}
+	case config.TransportWebSocket, "ws":
+		if server.URL == "" {
+			return []string{path + ".url: required for websocket servers"}
+		}
+		return checkURL(path+".url", server.URL, "ws", "wss", "replay")
+	case config.TransportSSE, config.TransportHTTP:
+		if server.URL == "" {
+			return []string{fmt.Sprintf("%s.url: required for %s servers", path, transport)}
+		}
+		return checkURL(path+".url", server.URL, "http", "https")
+	}
+	// Unknown transports are reported by the schema
+	return nil
+}
+
+// checkHTTP checks the users and trusted proxies of the REST gateway
+func checkHTTP(cfg config.HTTPConfig) []string {
+	var problems []string
+	names := make([]string, 0, len(cfg.Users))
+	for name := range cfg.Users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch {
+		case name == "" || strings.Contains(name, ":"):
+			problems = append(problems, fmt.Sprintf("http.users: %q is not a valid user name", name))
+		case cfg.Users[name] == "":
+			problems = append(problems, fmt.Sprintf("http.users.%s: password is empty", name))
+		}
+	}
+	for i, cidr := range cfg.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			problems = append(problems, fmt.Sprintf("http.trusted_proxies[%d]: %q is not a CIDR such as 10.0.0.0/8", i, cidr))
+		}
+	}
+	return problems
+}
+
+// checkURL checks that a URL parses and uses one of the given schemes
+func checkURL(path, raw string, schemes ...string) []string {
+	// References are checked again once they are expanded
+	if config.HasSecretRef(raw) {
+		return nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return []string{fmt.Sprintf("%s: %q is not an absolute URL such as %s://localhost:3000", path, raw, schemes[0])}
+	}
+
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			if u.Host == "" && scheme != "replay" {
+				return []string{fmt.Sprintf("%s: %q has no host", path, raw)}
+			}
+			return nil
+		}
+	}
+
+	problem := fmt.Sprintf("%s: scheme %q is not supported here (use %s)", path, u.Scheme, strings.Join(schemes, ", "))
+	if alternative, ok := map[string]string{"http": "ws", "https": "wss", "ws": "http", "wss": "https"}[u.Scheme]; ok && containsString(schemes, alternative) {
+		problem += fmt.Sprintf(", did you mean %q?", alternative+strings.TrimPrefix(raw, u.Scheme))
+	}
+	return []string{problem}
+}
+
+// checkDuration checks an optional Go duration such as 30s
+func checkDuration(path, value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		if _, numErr := strconv.ParseFloat(value, 64); numErr == nil {
+			return []string{fmt.Sprintf("%s: %q has no unit, did you mean %q?", path, value, value+"s")}
+		}
+		return []string{fmt.Sprintf("%s: %q is not a duration such as 30s or 1m", path, value)}
+	}
+	if d <= 0 {
+		return []string{fmt.Sprintf("%s: must be positive", path)}
+	}
+	return nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,46 +2,98 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/client"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
 	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/response"
-	"github.com/t-yamakoshi/go-mcp-client/pkg/infrastructure"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/metrics"
 )
 
 var _ IFMCPUsecase = (*MCPUsecase)(nil)
 
+// ErrToolNotFound is returned by GetTool for a tool the server does not list
+var ErrToolNotFound = errors.New("tool not found")
+
 type IFMCPUsecase interface {
 	EstablishConnection(ctx context.Context, serverURL string) error
+	EstablishServerConnection(ctx context.Context, name string, server config.ServerConfig) error
 	CloseConnection(ctx context.Context) error
+	SetTimeouts(dial, request time.Duration)
 	GetConnectionStatus(ctx context.Context) entity.ConnectionStatus
+	GetConnection(ctx context.Context) entity.Connection
 	InitializeProtocol(ctx context.Context, clientInfo entity.ClientInfo) (*response.InitializeResponse, error)
 	GetAvailableTools(ctx context.Context) ([]entity.Tool, error)
+	GetTool(ctx context.Context, name string) (*entity.Tool, error)
 	ExecuteTool(ctx context.Context, toolCall entity.ToolCall) (*entity.ToolResult, error)
+	Ping(ctx context.Context) error
+	SendRequest(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error)
+	GetAvailableResources(ctx context.Context) ([]entity.Resource, error)
+	ReadResource(ctx context.Context, uri string) ([]entity.ResourceContents, error)
+	GetAvailablePrompts(ctx context.Context) ([]entity.Prompt, error)
+	GetPrompt(ctx context.Context, request entity.PromptRequest) (*entity.PromptResult, error)
 	HandleIncomingMessage(ctx context.Context, message *entity.Message) error
 	SendOutgoingMessage(ctx context.Context, message *entity.Message) error
 	RegisterHandler(method string, handler MessageHandler)
+	SubscribeNotifications(listener MessageHandler) (unsubscribe func())
+	SubscribeConnectionState(listener ConnectionListener) (unsubscribe func())
+	StartRecording(ctx context.Context, path string) error
+	StopRecording(ctx context.Context) error
 }
 
 type MCPUsecase struct {
 	mcpRepo    repository.IFMCPRepository
 	configRepo repository.IFConfigRepository
+	audit      *AuditUsecase
+	policy     *PolicyUsecase
+	approval   *ApprovalUsecase
+	logger     *slog.Logger
 	mu         sync.RWMutex
 	handlers   map[string]MessageHandler
+	listeners  []notificationListener
+	watchers   []connectionWatcher
+	// tools caches the last tool list by name until the server reports a change
+	tools      map[string]entity.Tool
+	nextID     int
 	connection *entity.Connection
+	// serverName is the mcpServers entry of the connection, matched by the policy
+	serverName string
 }
 
 type MessageHandler func(*entity.Message) error
 
-func NewMCPUsecase(configRepo *infrastructure.ConfigRepositoryImpl, mcpRepo *infrastructure.MCPRepositoryImpl) *MCPUsecase {
-	return &MCPUsecase{
+// ConnectionListener receives a snapshot of the connection after every status change
+type ConnectionListener func(entity.Connection)
+
+// notificationListener is a subscriber registered through SubscribeNotifications
+type notificationListener struct {
+	id      int
+	handler MessageHandler
+}
+
+// connectionWatcher is a subscriber registered through SubscribeConnectionState
+type connectionWatcher struct {
+	id       int
+	listener ConnectionListener
+}
+
+// NewMCPUsecase creates the MCP usecase on top of a client session
+func NewMCPUsecase(configRepo repository.IFConfigRepository, mcpClient *client.Client, audit *AuditUsecase, policy *PolicyUsecase, approval *ApprovalUsecase) *MCPUsecase {
+	uc := &MCPUsecase{
 		configRepo: configRepo,
-		mcpRepo:    mcpRepo,
+		audit:      audit,
+		policy:     policy,
+		approval:   approval,
+		mcpRepo:    mcpClient,
 		handlers:   make(map[string]MessageHandler),
 		connection: &entity.Connection{
 			ID:        uuid.New().String(),
@@ -50,49 +102,111 @@ func NewMCPUsecase(configRepo *infrastructure.ConfigRepositoryImpl, mcpRepo *inf
 			UpdatedAt: time.Now(),
 		},
 	}
+	uc.logger = logging.Logger("usecase").With("session", uc.connection.ID)
+
+	// Route server initiated messages through the usecase handlers
+	mcpClient.SetDefaultHandler(func(message *entity.Message) error {
+		return uc.HandleIncomingMessage(context.Background(), message)
+	})
+	mcpClient.SetCloseHandler(uc.handleConnectionLost)
+
+	return uc
 }
 
 // EstablishConnection establishes a connection to the MCP server
 func (uc *MCPUsecase) EstablishConnection(ctx context.Context, serverURL string) error {
-	uc.mu.Lock()
-	defer uc.mu.Unlock()
+	return uc.establish("", serverURL, func() error {
+		return uc.mcpRepo.Connect(ctx, serverURL)
+	})
+}
 
-	// Update connection status
-	uc.connection.Status = entity.ConnectionStatusConnecting
-	uc.connection.ServerURL = serverURL
-	uc.connection.UpdatedAt = time.Now()
+// EstablishServerConnection establishes a connection to a server from the mcpServers configuration
+func (uc *MCPUsecase) EstablishServerConnection(ctx context.Context, name string, server config.ServerConfig) error {
+	address := server.Address()
+	if name != "" {
+		uc.logger.Info("connecting to MCP server", "server", name, "transport", server.TransportType())
+	}
+	return uc.establish(name, address, func() error {
+		return uc.mcpRepo.ConnectServer(ctx, server)
+	})
+}
+
+// establish runs connect while tracking the connection status
+func (uc *MCPUsecase) establish(name, address string, connect func() error) error {
+	uc.mu.Lock()
+	uc.connection.ServerURL = address
+	uc.serverName = name
+	uc.setStatus(entity.ConnectionStatusConnecting)
 
 	// Connect to the server
-	if err := uc.mcpRepo.Connect(ctx, serverURL); err != nil {
-		uc.connection.Status = entity.ConnectionStatusError
-		uc.connection.UpdatedAt = time.Now()
+	if err := connect(); err != nil {
+		uc.setStatus(entity.ConnectionStatusError)
+		uc.mu.Unlock()
+		uc.notifyConnectionState()
 		return fmt.Errorf("failed to establish connection: %w", err)
 	}
 
-	// Update connection status
-	uc.connection.Status = entity.ConnectionStatusConnected
-	uc.connection.UpdatedAt = time.Now()
+	uc.setStatus(entity.ConnectionStatusConnected)
+	uc.mu.Unlock()
+	uc.notifyConnectionState()
 
-	log.Printf("Successfully connected to MCP server: %s", serverURL)
+	uc.logger.Info("connected to MCP server", "address", address)
 	return nil
 }
 
+// SetTimeouts changes the dial and request timeouts of the session; zero keeps the current value
+func (uc *MCPUsecase) SetTimeouts(dial, request time.Duration) {
+	uc.mcpRepo.SetTimeouts(dial, request)
+}
+
 // CloseConnection closes the connection to the MCP server
 func (uc *MCPUsecase) CloseConnection(ctx context.Context) error {
 	uc.mu.Lock()
-	defer uc.mu.Unlock()
-
 	if err := uc.mcpRepo.Disconnect(); err != nil {
+		uc.mu.Unlock()
 		return fmt.Errorf("failed to close connection: %w", err)
 	}
 
-	uc.connection.Status = entity.ConnectionStatusDisconnected
-	uc.connection.UpdatedAt = time.Now()
+	uc.setStatus(entity.ConnectionStatusDisconnected)
+	uc.mu.Unlock()
+	uc.notifyConnectionState()
 
-	log.Println("Connection closed successfully")
+	uc.logger.Info("connection closed")
 	return nil
 }
 
+// handleConnectionLost marks the connection as failed when the server goes away
+func (uc *MCPUsecase) handleConnectionLost(err error) {
+	uc.mu.Lock()
+	uc.setStatus(entity.ConnectionStatusError)
+	uc.mu.Unlock()
+	uc.notifyConnectionState()
+
+	uc.logger.Warn("connection to MCP server lost", "error", err)
+}
+
+// setStatus updates the connection status. The caller must hold uc.mu.
+func (uc *MCPUsecase) setStatus(status entity.ConnectionStatus) {
+	uc.tools = nil
+	uc.connection.Status = status
+	uc.connection.UpdatedAt = time.Now()
+	metrics.SetConnectionStatus(uc.connection.ServerURL, status)
+}
+
+// notifyConnectionState sends the current connection to every state subscriber.
+// It must be called without holding uc.mu so subscribers may use the usecase.
+func (uc *MCPUsecase) notifyConnectionState() {
+	uc.mu.RLock()
+	snapshot := *uc.connection
+	watchers := make([]connectionWatcher, len(uc.watchers))
+	copy(watchers, uc.watchers)
+	uc.mu.RUnlock()
+
+	for _, watcher := range watchers {
+		watcher.listener(snapshot)
+	}
+}
+
 // GetConnectionStatus returns the current connection status
 func (uc *MCPUsecase) GetConnectionStatus(ctx context.Context) entity.ConnectionStatus {
 	uc.mu.RLock()
@@ -100,6 +214,13 @@ func (uc *MCPUsecase) GetConnectionStatus(ctx context.Context) entity.Connection
 	return uc.connection.Status
 }
 
+// GetConnection returns a snapshot of the current connection
+func (uc *MCPUsecase) GetConnection(ctx context.Context) entity.Connection {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return *uc.connection
+}
+
 // InitializeProtocol initializes the MCP protocol
 func (uc *MCPUsecase) InitializeProtocol(ctx context.Context, clientInfo entity.ClientInfo) (*response.InitializeResponse, error) {
 	uc.mu.RLock()
@@ -114,8 +235,16 @@ func (uc *MCPUsecase) InitializeProtocol(ctx context.Context, clientInfo entity.
 		return nil, fmt.Errorf("failed to initialize protocol: %w", err)
 	}
 
-	log.Printf("Protocol initialized with server: %s v%s",
-		response.ServerInfo.Name, response.ServerInfo.Version)
+	uc.mu.Lock()
+	serverInfo := response.ServerInfo
+	uc.connection.ServerInfo = &serverInfo
+	uc.connection.ProtocolVersion = response.ProtocolVersion
+	uc.connection.UpdatedAt = time.Now()
+	uc.mu.Unlock()
+
+	uc.logger.Info("protocol initialized",
+		"server_name", response.ServerInfo.Name, "server_version", response.ServerInfo.Version,
+		"protocol_version", response.ProtocolVersion)
 	return response, nil
 }
 
@@ -133,34 +262,257 @@ func (uc *MCPUsecase) GetAvailableTools(ctx context.Context) ([]entity.Tool, err
 		return nil, fmt.Errorf("failed to get available tools: %w", err)
 	}
 
-	log.Printf("Retrieved %d available tools", len(tools))
+	uc.mu.Lock()
+	uc.tools = make(map[string]entity.Tool, len(tools))
+	for _, tool := range tools {
+		uc.tools[tool.Name] = tool
+	}
+	uc.mu.Unlock()
+
+	uc.logger.Debug("retrieved tools", "count", len(tools))
 	return tools, nil
 }
 
-// ExecuteTool executes a tool on the server
-func (uc *MCPUsecase) ExecuteTool(ctx context.Context, toolCall entity.ToolCall) (*entity.ToolResult, error) {
+// GetTool returns the definition of one tool. The tool list is cached until
+// the server sends notifications/tools/list_changed or the connection changes.
+func (uc *MCPUsecase) GetTool(ctx context.Context, name string) (*entity.Tool, error) {
+	uc.mu.RLock()
+	tool, ok := uc.tools[name]
+	uc.mu.RUnlock()
+	if ok {
+		return &tool, nil
+	}
+
+	// Refresh in case the tool was added since the list was cached
+	if _, err := uc.GetAvailableTools(ctx); err != nil {
+		return nil, err
+	}
+
+	uc.mu.RLock()
+	tool, ok = uc.tools[name]
+	uc.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, name)
+	}
+	return &tool, nil
+}
+
+// ExecuteTool executes a tool on the server. Calls the policy or the tool's
+// destructiveHint marks as requiring confirmation wait for approval first. Every
+// call is recorded in the audit log, when one is open.
+func (uc *MCPUsecase) ExecuteTool(ctx context.Context, toolCall entity.ToolCall) (result *entity.ToolResult, err error) {
+	start := time.Now()
+	var approval *entity.Approval
+	defer func() { uc.auditToolCall(ctx, toolCall, start, approval, result, err) }()
+
 	uc.mu.RLock()
 	if uc.connection.Status != entity.ConnectionStatusConnected {
 		uc.mu.RUnlock()
 		return nil, fmt.Errorf("not connected to server")
 	}
+	serverName, serverAddress := uc.serverName, uc.connection.ServerURL
 	uc.mu.RUnlock()
+	server := serverName
+	if server == "" {
+		server = serverAddress
+	}
 
-	result, err := uc.mcpRepo.CallTool(ctx, toolCall)
+	if uc.audit != nil {
+		if err := uc.audit.CheckAudit(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	confirm, err := uc.checkPolicy(serverName, serverAddress, server, toolCall)
+	if err != nil {
+		return nil, err
+	}
+	if confirm == "" {
+		if confirm, err = uc.destructiveReason(ctx, toolCall.Name); err != nil {
+			return nil, err
+		}
+	}
+	if confirm != "" {
+		if uc.approval == nil {
+			return nil, &PolicyError{Tool: toolCall.Name, Server: server, Reason: confirm + " and nothing can approve it"}
+		}
+		// The request is shown to approvers, so its arguments are redacted as in the audit log
+		arguments := redactArguments(toolCall.Arguments, nil)
+		if uc.audit != nil {
+			arguments = uc.audit.RedactArguments(toolCall.Arguments)
+		}
+		decided, err := uc.approval.RequestApproval(ctx, entity.ApprovalRequest{
+			Session:   uc.connection.ID,
+			Server:    server,
+			Tool:      toolCall.Name,
+			Arguments: arguments,
+			Reason:    confirm,
+			Caller:    CallerFrom(ctx),
+		})
+		if decided.Decision != "" {
+			approval = &decided
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err = uc.mcpRepo.CallTool(ctx, toolCall)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute tool %s: %w", toolCall.Name, err)
 	}
 
-	log.Printf("Successfully executed tool: %s", toolCall.Name)
+	uc.logger.Info("executed tool", "tool", toolCall.Name)
+	return result, nil
+}
+
+// checkPolicy returns a PolicyError when the policy does not let a call reach the
+// server, or why the call needs confirmation when it does
+func (uc *MCPUsecase) checkPolicy(serverName, serverAddress, server string, toolCall entity.ToolCall) (string, error) {
+	if uc.policy == nil {
+		return "", nil
+	}
+
+	decision := uc.policy.Evaluate(serverName, serverAddress, toolCall)
+	if !decision.Allowed {
+		uc.logger.Warn("tool call denied by policy", "tool", toolCall.Name, "server", server, "reason", decision.Reason)
+		return "", &PolicyError{Tool: toolCall.Name, Server: server, Reason: decision.Reason}
+	}
+	if decision.Confirm {
+		return "the policy requires confirmation", nil
+	}
+	return "", nil
+}
+
+// destructiveReason returns why a tool needs confirmation when the server marks
+// it with destructiveHint, or "" when it does not. Without the annotations a
+// destructive tool cannot be told apart, so an unknown tool is an error.
+func (uc *MCPUsecase) destructiveReason(ctx context.Context, name string) (string, error) {
+	tool, err := uc.GetTool(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("cannot tell whether tool %s needs approval: %w", name, err)
+	}
+	if !tool.Annotations.Destructive() {
+		return "", nil
+	}
+	return "the server marks the tool as destructive", nil
+}
+
+// auditToolCall writes the audit record of a finished tool call
+func (uc *MCPUsecase) auditToolCall(ctx context.Context, toolCall entity.ToolCall, start time.Time, approval *entity.Approval, result *entity.ToolResult, callErr error) {
+	if uc.audit == nil {
+		return
+	}
+	uc.mu.RLock()
+	connection := *uc.connection
+	uc.mu.RUnlock()
+
+	if err := uc.audit.RecordToolCall(ctx, connection, toolCall, start, approval, result, callErr); err != nil {
+		uc.logger.Error("failed to audit tool call", "tool", toolCall.Name, "error", err)
+	}
+}
+
+// Ping checks that the server is responsive
+func (uc *MCPUsecase) Ping(ctx context.Context) error {
+	if err := uc.ensureConnected(); err != nil {
+		return err
+	}
+
+	if err := uc.mcpRepo.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping server: %w", err)
+	}
+	return nil
+}
+
+// SendRequest sends an arbitrary request to the server and returns the raw result
+func (uc *MCPUsecase) SendRequest(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+	if err := uc.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	result, err := uc.mcpRepo.Request(ctx, method, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+	return result, nil
+}
+
+// GetAvailableResources retrieves available resources from the server
+func (uc *MCPUsecase) GetAvailableResources(ctx context.Context) ([]entity.Resource, error) {
+	if err := uc.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	resources, err := uc.mcpRepo.ListResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get available resources: %w", err)
+	}
+
+	uc.logger.Debug("retrieved resources", "count", len(resources))
+	return resources, nil
+}
+
+// ReadResource reads the contents of a resource from the server
+func (uc *MCPUsecase) ReadResource(ctx context.Context, uri string) ([]entity.ResourceContents, error) {
+	if err := uc.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	contents, err := uc.mcpRepo.ReadResource(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource %s: %w", uri, err)
+	}
+	return contents, nil
+}
+
+// GetAvailablePrompts retrieves available prompts from the server
+func (uc *MCPUsecase) GetAvailablePrompts(ctx context.Context) ([]entity.Prompt, error) {
+	if err := uc.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	prompts, err := uc.mcpRepo.ListPrompts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get available prompts: %w", err)
+	}
+
+	uc.logger.Debug("retrieved prompts", "count", len(prompts))
+	return prompts, nil
+}
+
+// GetPrompt renders a prompt on the server
+func (uc *MCPUsecase) GetPrompt(ctx context.Context, request entity.PromptRequest) (*entity.PromptResult, error) {
+	if err := uc.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	result, err := uc.mcpRepo.GetPrompt(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt %s: %w", request.Name, err)
+	}
 	return result, nil
 }
 
 // HandleIncomingMessage handles incoming messages from the server
 func (uc *MCPUsecase) HandleIncomingMessage(ctx context.Context, message *entity.Message) error {
+	if message.Method == "notifications/tools/list_changed" {
+		uc.mu.Lock()
+		uc.tools = nil
+		uc.mu.Unlock()
+	}
+
 	uc.mu.RLock()
 	handler, exists := uc.handlers[message.Method]
+	listeners := make([]notificationListener, len(uc.listeners))
+	copy(listeners, uc.listeners)
 	uc.mu.RUnlock()
 
+	for _, listener := range listeners {
+		if err := listener.handler(message); err != nil {
+			uc.logger.Warn("notification listener failed", "method", message.Method, "error", err)
+		}
+	}
+
 	if exists {
 		return handler(message)
 	}
@@ -174,7 +526,9 @@ func (uc *MCPUsecase) HandleIncomingMessage(ctx context.Context, message *entity
 	case "tools/call":
 		return uc.handleToolsCall(ctx, message)
 	default:
-		log.Printf("Unhandled message method: %s", message.Method)
+		if len(listeners) == 0 {
+			uc.logger.Debug("unhandled message", "method", message.Method, "request_id", message.ID)
+		}
 	}
 
 	return nil
@@ -199,9 +553,71 @@ func (uc *MCPUsecase) RegisterHandler(method string, handler MessageHandler) {
 	uc.handlers[method] = handler
 }
 
+// SubscribeNotifications registers a listener that receives every message
+// initiated by the server. The returned function removes the listener.
+func (uc *MCPUsecase) SubscribeNotifications(listener MessageHandler) func() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.nextID++
+	id := uc.nextID
+	uc.listeners = append(uc.listeners, notificationListener{id: id, handler: listener})
+
+	return func() {
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+		for i, l := range uc.listeners {
+			if l.id == id {
+				uc.listeners = append(uc.listeners[:i], uc.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SubscribeConnectionState registers a listener that is called after every
+// connection status change. The returned function removes the listener.
+func (uc *MCPUsecase) SubscribeConnectionState(listener ConnectionListener) func() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.nextID++
+	id := uc.nextID
+	uc.watchers = append(uc.watchers, connectionWatcher{id: id, listener: listener})
+
+	return func() {
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+		for i, w := range uc.watchers {
+			if w.id == id {
+				uc.watchers = append(uc.watchers[:i], uc.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// StartRecording records all traffic with the server to a file
+func (uc *MCPUsecase) StartRecording(ctx context.Context, path string) error {
+	if err := uc.mcpRepo.StartRecording(path); err != nil {
+		return fmt.Errorf("failed to start recording: %w", err)
+	}
+
+	uc.logger.Info("recording traffic", "path", path)
+	return nil
+}
+
+// StopRecording stops recording traffic
+func (uc *MCPUsecase) StopRecording(ctx context.Context) error {
+	if err := uc.mcpRepo.StopRecording(); err != nil {
+		return fmt.Errorf("failed to stop recording: %w", err)
+	}
+	return nil
+}
+
 // handlePing handles ping messages
 func (uc *MCPUsecase) handlePing(ctx context.Context, message *entity.Message) error {
-	log.Println("Received ping, sending pong")
+	uc.logger.Debug("received ping, sending pong", "request_id", message.ID)
 	pongMsg := &entity.Message{
 		ID:     message.ID,
 		Method: "pong",
@@ -211,14 +627,24 @@ func (uc *MCPUsecase) handlePing(ctx context.Context, message *entity.Message) e
 
 // handleToolsList handles tools/list messages
 func (uc *MCPUsecase) handleToolsList(ctx context.Context, message *entity.Message) error {
-	log.Println("Received tools/list request")
+	uc.logger.Debug("received tools/list request", "request_id", message.ID)
 	// Implementation would depend on specific requirements
 	return nil
 }
 
 // handleToolsCall handles tools/call messages
 func (uc *MCPUsecase) handleToolsCall(ctx context.Context, message *entity.Message) error {
-	log.Println("Received tools/call request")
+	uc.logger.Debug("received tools/call request", "request_id", message.ID)
 	// Implementation would depend on specific requirements
 	return nil
 }
+
+// ensureConnected returns an error unless the connection is established
+func (uc *MCPUsecase) ensureConnected() error {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	if uc.connection.Status != entity.ConnectionStatusConnected {
+		return fmt.Errorf("not connected to server")
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// PolicyError is returned by ExecuteTool for a call the policy does not allow;
+// the call never reaches the server
+type PolicyError struct {
+	Tool   string
+	Server string
+	Reason string
+}
+
+// Error implements error
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("tool %s is not allowed by policy: %s", e.Tool, e.Reason)
+}
+
+// PolicyDecision is the outcome of evaluating the policy for a tool call
+type PolicyDecision struct {
+	Allowed bool
+	// Confirm is set when an allowed call needs approval first
+	Confirm bool
+	// Reason explains a denial
+	Reason string
+}
+
+// PolicyUsecase evaluates the tool call policy of the configuration. It is
+// shared by every session so that a reloaded policy applies to all of them.
+type PolicyUsecase struct {
+	mu     sync.RWMutex
+	policy *compiledPolicy
+}
+
+// compiledPolicy is a policy with its patterns parsed
+type compiledPolicy struct {
+	deny  bool
+	rules []compiledRule
+}
+
+// compiledRule is a policy rule with its argument patterns compiled
+type compiledRule struct {
+	index    int
+	rule     config.PolicyRule
+	patterns map[string]*regexp.Regexp
+	// arguments are the constrained argument names in a stable order
+	arguments []string
+}
+
+// NewPolicyUsecase creates a policy usecase that allows every call until SetPolicy
+func NewPolicyUsecase() *PolicyUsecase {
+	return &PolicyUsecase{policy: &compiledPolicy{}}
+}
+
+// SetPolicy replaces the policy; an invalid policy keeps the previous one
+func (uc *PolicyUsecase) SetPolicy(cfg config.PolicyConfig) error {
+	policy, problems := compilePolicy(cfg, "policy")
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	uc.mu.Lock()
+	uc.policy = policy
+	uc.mu.Unlock()
+	return nil
+}
+
+// Evaluate decides whether a tool call may be sent to a server, which is
+// matched by both its configured name and its address
+func (uc *PolicyUsecase) Evaluate(serverName, serverAddress string, toolCall entity.ToolCall) PolicyDecision {
+	uc.mu.RLock()
+	policy := uc.policy
+	uc.mu.RUnlock()
+
+	for _, rule := range policy.rules {
+		if !rule.matches(serverName, serverAddress, toolCall.Name) {
+			continue
+		}
+		if rule.rule.Action == config.PolicyDeny {
+			reason := rule.rule.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("denied by rule %d", rule.index)
+			}
+			return PolicyDecision{Reason: reason}
+		}
+		if problem := rule.checkArguments(toolCall.Arguments); problem != "" {
+			return PolicyDecision{Reason: problem}
+		}
+		return PolicyDecision{Allowed: true, Confirm: rule.rule.Confirm}
+	}
+
+	if policy.deny {
+		return PolicyDecision{Reason: "no rule allows it and the default is deny"}
+	}
+	return PolicyDecision{Allowed: true}
+}
+
+// compilePolicy parses the patterns of a policy and returns one problem per invalid value
+func compilePolicy(cfg config.PolicyConfig, prefix string) (*compiledPolicy, []string) {
+	var problems []string
+	policy := &compiledPolicy{deny: cfg.Default == config.PolicyDeny}
+
+	for i, rule := range cfg.Rules {
+		rulePath := fmt.Sprintf("%s.rules[%d]", prefix, i)
+		compiled := compiledRule{index: i, rule: rule, patterns: map[string]*regexp.Regexp{}}
+
+		if _, err := path.Match(rule.Server, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.server: invalid glob %q", rulePath, rule.Server))
+		}
+		if _, err := path.Match(rule.Tool, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.tool: invalid glob %q", rulePath, rule.Tool))
+		}
+		if rule.Action != config.PolicyAllow && rule.Action != config.PolicyDeny {
+			problems = append(problems, fmt.Sprintf("%s.action: must be allow or deny", rulePath))
+		}
+
+		names := make([]string, 0, len(rule.Arguments))
+		for name := range rule.Arguments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			constraint := rule.Arguments[name]
+			argumentPath := fmt.Sprintf("%s.arguments.%s", rulePath, name)
+			if constraint.Pattern != "" {
+				re, err := regexp.Compile(constraint.Pattern)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s.pattern: %v", argumentPath, err))
+				}
+				compiled.patterns[name] = re
+			}
+			for _, root := range constraint.Under {
+				if !filepath.IsAbs(root) {
+					problems = append(problems, fmt.Sprintf("%s.under: %q is not an absolute path", argumentPath, root))
+				}
+			}
+			compiled.arguments = append(compiled.arguments, name)
+		}
+		policy.rules = append(policy.rules, compiled)
+	}
+	return policy, problems
+}
+
+// matches reports whether the rule applies to a tool of a server
+func (r compiledRule) matches(serverName, serverAddress, tool string) bool {
+	if !globMatch(r.rule.Tool, tool) {
+		return false
+	}
+	return r.rule.Server == "" || globMatch(r.rule.Server, serverName) || globMatch(r.rule.Server, serverAddress)
+}
+
+// checkArguments returns the first argument constraint a call violates, or ""
+func (r compiledRule) checkArguments(arguments map[string]interface{}) string {
+	for _, name := range r.arguments {
+		constraint := r.rule.Arguments[name]
+		value, ok := arguments[name]
+		if !ok {
+			if constraint.Required {
+				return fmt.Sprintf("argument %s is required", name)
+			}
+			continue
+		}
+
+		if len(constraint.Enum) > 0 && !containsArgument(constraint.Enum, value) {
+			return fmt.Sprintf("argument %s must be one of %s", name, compactJSON(constraint.Enum))
+		}
+		if re := r.patterns[name]; re != nil {
+			s, isString := value.(string)
+			if !isString || !re.MatchString(s) {
+				return fmt.Sprintf("argument %s must match %s", name, constraint.Pattern)
+			}
+		}
+		if len(constraint.Under) > 0 {
+			s, isString := value.(string)
+			if !isString || !underAny(s, constraint.Under) {
+				return fmt.Sprintf("argument %s must be an absolute path under %s", name, strings.Join(constraint.Under, ", "))
+			}
+		}
+	}
+	return ""
+}
+
+// globMatch matches a name against a glob; an empty glob matches everything
+func globMatch(glob, name string) bool {
+	if glob == "" {
+		return true
+	}
+	matched, _ := path.Match(glob, name)
+	return matched
+}
+
+// underAny reports whether p is an absolute path inside one of the roots once
+// cleaned, so that ".." cannot escape a root. Symbolic links on the server are not resolved.
+func underAny(p string, roots []string) bool {
+	if !filepath.IsAbs(p) {
+		return false
+	}
+	p = filepath.Clean(p)
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		if p == root || strings.HasPrefix(p, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsArgument reports whether an argument value equals one of the allowed
+// values; numbers compare by value whatever their decoded type
+func containsArgument(allowed []interface{}, value interface{}) bool {
+	for _, candidate := range allowed {
+		if reflect.DeepEqual(candidate, value) || compactJSON(candidate) == compactJSON(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+)
+
+// newTestPolicy returns a policy usecase with cfg applied
+func newTestPolicy(t *testing.T, cfg config.PolicyConfig) *PolicyUsecase {
+	t.Helper()
+	uc := NewPolicyUsecase()
+	if err := uc.SetPolicy(cfg); err != nil {
+		t.Fatal(err)
+	}
+	return uc
+}
+
+func TestPolicyMatchesServerAndTool(t *testing.T) {
+	uc := newTestPolicy(t, config.PolicyConfig{
+		Default: config.PolicyDeny,
+		Rules: []config.PolicyRule{
+			{Server: "fs*", Tool: "read_*", Action: config.PolicyAllow},
+			{Server: "ws://localhost:*", Tool: "echo", Action: config.PolicyAllow},
+			{Tool: "ping", Action: config.PolicyAllow},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		server  string
+		address string
+		tool    string
+		allowed bool
+	}{
+		{"server name glob", "fs-home", "stdio:mcp-fs", "read_file", true},
+		{"tool glob does not match", "fs-home", "stdio:mcp-fs", "write_file", false},
+		{"server name does not match", "github", "stdio:mcp-fs", "read_file", false},
+		{"server address glob", "", "ws://localhost:3000", "echo", true},
+		{"server address does not match", "", "ws://example.com:3000", "echo", false},
+		{"empty server glob matches any server", "anything", "ws://example.com", "ping", true},
+		{"glob does not cross slashes", "fs/nested", "", "read_file", false},
+		{"default deny", "other", "", "unknown", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := uc.Evaluate(tt.server, tt.address, entity.ToolCall{Name: tt.tool})
+			if decision.Allowed != tt.allowed {
+				t.Errorf("allowed = %v (%s), want %v", decision.Allowed, decision.Reason, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestPolicyFirstMatchingRuleDecides(t *testing.T) {
+	uc := newTestPolicy(t, config.PolicyConfig{
+		Rules: []config.PolicyRule{
+			{Tool: "delete_*", Action: config.PolicyDeny, Reason: "deleting is not allowed"},
+			{Tool: "delete_draft", Action: config.PolicyAllow},
+			{Tool: "*", Action: config.PolicyAllow, Confirm: true},
+			{Tool: "echo", Action: config.PolicyAllow},
+		},
+	})
+
+	decision := uc.Evaluate("", "", entity.ToolCall{Name: "delete_draft"})
+	if decision.Allowed || decision.Reason != "deleting is not allowed" {
+		t.Errorf("delete_draft = %+v, want denied by the first rule", decision)
+	}
+	decision = uc.Evaluate("", "", entity.ToolCall{Name: "echo"})
+	if !decision.Allowed || !decision.Confirm {
+		t.Errorf("echo = %+v, want allowed with confirmation by the catch-all rule", decision)
+	}
+}
+
+func TestPolicyDefault(t *testing.T) {
+	if decision := NewPolicyUsecase().Evaluate("", "", entity.ToolCall{Name: "echo"}); !decision.Allowed {
+		t.Errorf("a new policy denies calls: %+v", decision)
+	}
+	uc := newTestPolicy(t, config.PolicyConfig{Default: config.PolicyDeny})
+	if decision := uc.Evaluate("", "", entity.ToolCall{Name: "echo"}); decision.Allowed {
+		t.Error("default deny allows calls no rule matches")
+	}
+	uc = newTestPolicy(t, config.PolicyConfig{Rules: []config.PolicyRule{{Tool: "rm", Action: config.PolicyDeny}}})
+	if decision := uc.Evaluate("", "", entity.ToolCall{Name: "rm"}); decision.Allowed || !strings.Contains(decision.Reason, "rule 0") {
+		t.Errorf("rm = %+v, want denied by rule 0", decision)
+	}
+}
+
+func TestPolicyArgumentConstraints(t *testing.T) {
+	uc := newTestPolicy(t, config.PolicyConfig{
+		Default: config.PolicyDeny,
+		Rules: []config.PolicyRule{{
+			Tool:   "write_file",
+			Action: config.PolicyAllow,
+			Arguments: map[string]config.ArgumentConstraint{
+				"path":     {Required: true, Under: []string{"/srv/data", "/tmp/"}},
+				"mode":     {Enum: []interface{}{"overwrite", "append"}},
+				"encoding": {Pattern: "^utf-(8|16)$"},
+				"retries":  {Enum: []interface{}{1, 2, 3}},
+			},
+		}},
+	})
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		problem   string
+	}{
+		{"allowed", map[string]interface{}{"path": "/srv/data/report.txt", "mode": "append", "encoding": "utf-8"}, ""},
+		{"root itself", map[string]interface{}{"path": "/srv/data"}, ""},
+		{"root with trailing slash", map[string]interface{}{"path": "/tmp/x"}, ""},
+		{"missing required", map[string]interface{}{"mode": "append"}, "argument path is required"},
+		{"outside roots", map[string]interface{}{"path": "/etc/passwd"}, "argument path must be an absolute path under"},
+		{"sibling with a common prefix", map[string]interface{}{"path": "/srv/database/dump"}, "argument path must be an absolute path under"},
+		{"traversal", map[string]interface{}{"path": "/srv/data/../../etc/passwd"}, "argument path must be an absolute path under"},
+		{"relative path", map[string]interface{}{"path": "srv/data/report.txt"}, "argument path must be an absolute path under"},
+		{"not a string", map[string]interface{}{"path": 42.0}, "argument path must be an absolute path under"},
+		{"enum", map[string]interface{}{"path": "/tmp/x", "mode": "truncate"}, `argument mode must be one of ["overwrite","append"]`},
+		{"enum number decoded as float", map[string]interface{}{"path": "/tmp/x", "retries": 2.0}, ""},
+		{"enum number out of range", map[string]interface{}{"path": "/tmp/x", "retries": 4.0}, "argument retries must be one of"},
+		{"pattern", map[string]interface{}{"path": "/tmp/x", "encoding": "latin-1"}, "argument encoding must match ^utf-(8|16)$"},
+		{"pattern on a non-string", map[string]interface{}{"path": "/tmp/x", "encoding": 8.0}, "argument encoding must match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := uc.Evaluate("", "", entity.ToolCall{Name: "write_file", Arguments: tt.arguments})
+			if tt.problem == "" {
+				if !decision.Allowed {
+					t.Errorf("denied: %s", decision.Reason)
+				}
+				return
+			}
+			if decision.Allowed || !strings.HasPrefix(decision.Reason, tt.problem) {
+				t.Errorf("decision = %+v, want denied with %q", decision, tt.problem)
+			}
+		})
+	}
+}
+
+func TestUnderAny(t *testing.T) {
+	roots := []string{"/srv/data", "/home/user/"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/srv/data", true},
+		{"/srv/data/", true},
+		{"/srv/data/a/b.txt", true},
+		{"/srv/data/./a", true},
+		{"/srv/data/a/../b", true},
+		{"/home/user/notes", true},
+		{"/srv/data/..", false},
+		{"/srv/data/../secret", false},
+		{"/srv/data/a/../../../etc/shadow", false},
+		{"/srv/database", false},
+		{"/srv", false},
+		{"/home/username", false},
+		{"srv/data/a", false},
+		{"../srv/data/a", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := underAny(tt.path, roots); got != tt.want {
+			t.Errorf("underAny(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSetPolicyRejectsInvalidPolicy(t *testing.T) {
+	uc := newTestPolicy(t, config.PolicyConfig{Rules: []config.PolicyRule{{Tool: "echo", Action: config.PolicyDeny}}})
+
+	err := uc.SetPolicy(config.PolicyConfig{Rules: []config.PolicyRule{
+		{Tool: "[", Action: config.PolicyAllow},
+		{Tool: "echo", Action: "maybe"},
+		{Tool: "write_file", Action: config.PolicyAllow, Arguments: map[string]config.ArgumentConstraint{
+			"path":     {Under: []string{"relative/dir"}},
+			"encoding": {Pattern: "("},
+		}},
+	}})
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("SetPolicy = %v, want a ConfigError", err)
+	}
+	want := []string{
+		`policy.rules[0].tool: invalid glob "["`,
+		"policy.rules[1].action: must be allow or deny",
+		"policy.rules[2].arguments.encoding.pattern:",
+		`policy.rules[2].arguments.path.under: "relative/dir" is not an absolute path`,
+	}
+	if len(configErr.Problems) != len(want) {
+		t.Fatalf("problems = %q, want %d", configErr.Problems, len(want))
+	}
+	for i, problem := range want {
+		if !strings.HasPrefix(configErr.Problems[i], problem) {
+			t.Errorf("problem %d = %q, want %q", i, configErr.Problems[i], problem)
+		}
+	}
+
+	// The previous policy stays in effect
+	if decision := uc.Evaluate("", "", entity.ToolCall{Name: "echo"}); decision.Allowed {
+		t.Error("an invalid policy replaced the previous one")
+	}
+}
@@ -4,5 +4,9 @@ import "github.com/google/wire"
 
 var UsecaseSet = wire.NewSet(
 	NewMCPUsecase,
+	NewAuditUsecase,
+	NewPolicyUsecase,
+	NewApprovalUsecase,
 	NewConfigUsecase,
+	NewServerPool,
 )
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ValidationError lists every way a value does not match a tool's input schema
+type ValidationError struct {
+	Tool     string
+	Problems []string
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid arguments for tool %s: %s", e.Tool, strings.Join(e.Problems, "; "))
+}
+
+// validateSchema checks a decoded JSON value against the subset of JSON Schema
+// used by tool input schemas and returns one problem per mismatch
+func validateSchema(schema map[string]interface{}, value interface{}, path string) []string {
+	if schema == nil {
+		return nil
+	}
+
+	var problems []string
+	if types := schemaTypes(schema); len(types) > 0 && !matchesAnyType(value, types) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonType(value))}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		if s, ok := value.(string); ok {
+			if suggestion := suggest(s, enumStrings(enum)); suggestion != "" {
+				problems = append(problems, fmt.Sprintf("%s: %q is not valid, did you mean %q?", path, s, suggestion))
+				return problems
+			}
+		}
+		problems = append(problems, fmt.Sprintf("%s: %s is not one of %s", path, compactJSON(value), compactJSON(enum)))
+	}
+
+	if minLength, ok := schema["minLength"].(float64); ok {
+		if s, isString := value.(string); isString && float64(len([]rune(s))) < minLength {
+			if minLength == 1 {
+				problems = append(problems, fmt.Sprintf("%s: must not be empty", path))
+			} else {
+				problems = append(problems, fmt.Sprintf("%s: must be at least %g characters", path, minLength))
+			}
+		}
+	}
+
+	if minimum, ok := schema["minimum"].(float64); ok {
+		if n, isNumber := value.(float64); isNumber && n < minimum {
+			problems = append(problems, fmt.Sprintf("%s: must be at least %g", path, minimum))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		problems = append(problems, validateObject(schema, v, path)...)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				problems = append(problems, validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+// validateObject checks required properties, property schemas and additionalProperties
+func validateObject(schema map[string]interface{}, value map[string]interface{}, path string) []string {
+	var problems []string
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, item := range required {
+			name, _ := item.(string)
+			if _, present := value[name]; !present {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := joinPath(path, key)
+		if prop, ok := properties[key].(map[string]interface{}); ok {
+			problems = append(problems, validateSchema(prop, value[key], child)...)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				problems = append(problems, unknownProperty(child, key, properties))
+			}
+		case map[string]interface{}:
+			problems = append(problems, validateSchema(additional, value[key], child)...)
+		}
+	}
+	return problems
+}
+
+// unknownProperty describes a property that the schema does not allow,
+// suggesting a defined property with a similar name
+func unknownProperty(path, key string, properties map[string]interface{}) string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if suggestion := suggest(key, names); suggestion != "" {
+		return fmt.Sprintf("%s: unknown property, did you mean %q?", path, suggestion)
+	}
+	return fmt.Sprintf("%s: unknown property", path)
+}
+
+// suggest returns the candidate closest to value when it is a likely typo or
+// abbreviation of it, or "" when no candidate is close enough
+func suggest(value string, candidates []string) string {
+	lower := strings.ToLower(value)
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		c := strings.ToLower(candidate)
+		distance := editDistance(lower, c)
+		if len(lower) >= 3 && strings.HasPrefix(c, lower) {
+			distance = 1
+		}
+		if distance > 2 || distance >= len(c) {
+			continue
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur := make([]int, len(br)+1)
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(br)]
+}
+
+// enumStrings returns the string values of an enum
+func enumStrings(enum []interface{}) []string {
+	values := make([]string, 0, len(enum))
+	for _, item := range enum {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// schemaTypes returns the types allowed by a schema
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// matchesAnyType reports whether a decoded JSON value has one of the given types
+func matchesAnyType(value interface{}, types []string) bool {
+	actual := jsonType(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON Schema type of a decoded JSON value
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// containsValue reports whether value equals one of the enum values
+func containsValue(enum []interface{}, value interface{}) bool {
+	for _, item := range enum {
+		if compactJSON(item) == compactJSON(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compactJSON formats a value for error messages
+func compactJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// joinPath appends a property name to a path such as args.location
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
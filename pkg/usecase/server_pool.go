@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+
+	"github.com/t-yamakoshi/go-mcp-client/pkg/client"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/config"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/entity"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/domain/repository"
+	"github.com/t-yamakoshi/go-mcp-client/pkg/logging"
+)
+
+// ServerPool keeps a session open to every enabled server of the configuration and
+// applies configuration changes by connecting new servers, disconnecting removed
+// ones and reconnecting changed ones. The primary session, which the commands and
+// the gateway use, follows the active server; the gateway serves the other
+// sessions under /servers/{name}.
+type ServerPool struct {
+	configRepo repository.IFConfigRepository
+	primary    *MCPUsecase
+	logger     *slog.Logger
+	mu         sync.Mutex
+	// active is the server the primary session is connected to
+	active     config.ServerConfig
+	activeName string
+	clientInfo entity.ClientInfo
+	sessions   map[string]*pooledSession
+}
+
+// pooledSession is the session of one non-primary server
+type pooledSession struct {
+	server  config.ServerConfig
+	session *MCPUsecase
+}
+
+// NewServerPool creates a pool around the primary session
+func NewServerPool(configRepo repository.IFConfigRepository, primary *MCPUsecase) *ServerPool {
+	return &ServerPool{
+		configRepo: configRepo,
+		primary:    primary,
+		logger:     logging.Logger("usecase"),
+		sessions:   make(map[string]*pooledSession),
+	}
+}
+
+// Start records that the primary session is connected to the active server of
+// resolved and opens sessions to the other enabled servers
+func (p *ServerPool) Start(ctx context.Context, resolved *config.Resolved) error {
+	name, server, err := resolved.ActiveServer()
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.active, p.activeName = server, name
+	p.clientInfo = resolved.Config.ClientInfo
+	p.mu.Unlock()
+
+	return p.Apply(ctx, resolved)
+}
+
+// Apply brings the open sessions in line with a configuration. Servers that fail
+// to connect are reported in the returned error; the others are still applied.
+func (p *ServerPool) Apply(ctx context.Context, resolved *config.Resolved) error {
+	activeName, active, err := resolved.ActiveServer()
+	if err != nil {
+		return err
+	}
+	cfg := resolved.Config
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	desired := make(map[string]config.ServerConfig)
+	for _, name := range cfg.ServerNames() {
+		if server := cfg.MCPServers[name]; !server.Disabled && name != activeName {
+			desired[name] = server
+		}
+	}
+
+	// Sessions announce the client info in initialize, so a change reconnects all of them
+	clientInfoChanged := cfg.ClientInfo != p.clientInfo
+	p.clientInfo = cfg.ClientInfo
+
+	for name, pooled := range p.sessions {
+		if server, ok := desired[name]; ok && reflect.DeepEqual(server, pooled.server) && !clientInfoChanged {
+			continue
+		}
+		if err := pooled.session.CloseConnection(ctx); err != nil {
+			p.logger.Warn("failed to disconnect from server", "server", name, "error", err)
+		}
+		delete(p.sessions, name)
+		p.logger.Info("disconnected from server", "server", name)
+	}
+
+	var errs []error
+	dial, request := cfg.Timeouts()
+	p.primary.SetTimeouts(dial, request)
+	for _, pooled := range p.sessions {
+		pooled.session.SetTimeouts(dial, request)
+	}
+
+	if activeName != p.activeName || !reflect.DeepEqual(active, p.active) || clientInfoChanged {
+		if err := p.primary.CloseConnection(ctx); err != nil {
+			p.logger.Warn("failed to disconnect the primary session", "error", err)
+		}
+		p.active, p.activeName = active, activeName
+		if err := connectSession(ctx, p.primary, activeName, active, cfg.ClientInfo); err != nil {
+			errs = append(errs, fmt.Errorf("primary server: %w", err))
+		}
+	}
+
+	for _, name := range cfg.ServerNames() {
+		server, ok := desired[name]
+		if _, open := p.sessions[name]; !ok || open {
+			continue
+		}
+
+		session := NewMCPUsecase(p.configRepo, client.New(client.WithLogger(logging.Logger("transport").With("server", name))), p.primary.audit, p.primary.policy, p.primary.approval)
+		session.SetTimeouts(dial, request)
+		if err := connectSession(ctx, session, name, server, cfg.ClientInfo); err != nil {
+			errs = append(errs, fmt.Errorf("server %q: %w", name, err))
+			continue
+		}
+		p.sessions[name] = &pooledSession{server: server, session: session}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Session returns the open session of a server; the active server's session is the primary one
+func (p *ServerPool) Session(name string) (*MCPUsecase, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if name == p.activeName {
+		return p.primary, true
+	}
+	pooled, ok := p.sessions[name]
+	if !ok {
+		return nil, false
+	}
+	return pooled.session, true
+}
+
+// Close disconnects every session except the primary one
+func (p *ServerPool) Close(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for name, pooled := range p.sessions {
+		if err := pooled.session.CloseConnection(ctx); err != nil {
+			p.logger.Warn("failed to disconnect from server", "server", name, "error", err)
+		}
+		delete(p.sessions, name)
+	}
+}
+
+// connectSession connects a session to a server and initializes the protocol
+func connectSession(ctx context.Context, session *MCPUsecase, name string, server config.ServerConfig, clientInfo entity.ClientInfo) error {
+	if err := session.EstablishServerConnection(ctx, name, server); err != nil {
+		return err
+	}
+	if _, err := session.InitializeProtocol(ctx, clientInfo); err != nil {
+		if closeErr := session.CloseConnection(ctx); closeErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to disconnect: %w", closeErr))
+		}
+		return err
+	}
+	return nil
+}
@@ -81,10 +81,23 @@ func handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			handleToolsList(conn, &msg)
 		case "tools/call":
 			handleToolsCall(conn, &msg)
+		case "resources/list":
+			handleResourcesList(conn, &msg)
+		case "resources/read":
+			handleResourcesRead(conn, &msg)
+		case "prompts/list":
+			handlePromptsList(conn, &msg)
+		case "prompts/get":
+			handlePromptsGet(conn, &msg)
 		case "ping":
 			handlePing(conn, &msg)
+		case "notifications/initialized":
+			log.Println("Client initialized")
 		default:
 			log.Printf("Unknown method: %s", msg.Method)
+			if msg.ID != "" {
+				writeError(conn, &msg, -32601, fmt.Sprintf("Method not found: %s", msg.Method))
+			}
 		}
 	}
 }
@@ -101,7 +114,9 @@ func handleInitialize(conn *websocket.Conn, msg *Message) {
 	response := InitializeResponse{
 		ProtocolVersion: "2024-11-05",
 		Capabilities: map[string]interface{}{
-			"tools": map[string]interface{}{},
+			"tools":     map[string]interface{}{},
+			"resources": map[string]interface{}{},
+			"prompts":   map[string]interface{}{},
 		},
 		ServerInfo: struct {
 			Name    string `json:"name"`
@@ -139,6 +154,25 @@ func handleToolsList(conn *websocket.Conn, msg *Message) {
 				"required": []string{"message"},
 			},
 		},
+		{
+			"name":        "add",
+			"description": "Add two numbers",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"a": map[string]interface{}{"type": "number"},
+					"b": map[string]interface{}{"type": "number"},
+				},
+				"required": []string{"a", "b"},
+			},
+			"outputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"sum": map[string]interface{}{"type": "number"},
+				},
+				"required": []string{"sum"},
+			},
+		},
 	}
 
 	result, _ := json.Marshal(map[string]interface{}{
@@ -173,6 +207,7 @@ func handleToolsCall(conn *websocket.Conn, msg *Message) {
 	var result interface{}
 	if toolCall.Name == "echo" {
 		if message, ok := toolCall.Arguments["message"].(string); ok {
+			sendLogNotification(conn, "info", fmt.Sprintf("echo called with %q", message))
 			result = map[string]interface{}{
 				"content": []map[string]interface{}{
 					{
@@ -185,6 +220,37 @@ func handleToolsCall(conn *websocket.Conn, msg *Message) {
 		}
 	}
 
+	if toolCall.Name == "add" {
+		a, okA := toolCall.Arguments["a"].(float64)
+		b, okB := toolCall.Arguments["b"].(float64)
+		if okA && okB {
+			sum := a + b
+			result = map[string]interface{}{
+				"content": []map[string]interface{}{
+					{
+						"type": "text",
+						"text": fmt.Sprintf(`{"sum":%g}`, sum),
+					},
+				},
+				"structuredContent": map[string]interface{}{"sum": sum},
+				"isError":           false,
+			}
+		}
+	}
+
+	// 未知のツールや引数の誤りはツールエラーとして返す
+	if result == nil {
+		result = map[string]interface{}{
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": fmt.Sprintf("Unknown tool or invalid arguments: %s", toolCall.Name),
+				},
+			},
+			"isError": true,
+		}
+	}
+
 	resultData, _ := json.Marshal(result)
 	responseMsg := Message{
 		ID:     msg.ID,
@@ -197,6 +263,135 @@ func handleToolsCall(conn *websocket.Conn, msg *Message) {
 	}
 }
 
+// テスト用のリソース
+var resources = map[string]string{
+	"test://hello.txt": "Hello from the MCP test server!",
+}
+
+func handleResourcesList(conn *websocket.Conn, msg *Message) {
+	list := []map[string]interface{}{}
+	for uri := range resources {
+		list = append(list, map[string]interface{}{
+			"uri":      uri,
+			"name":     uri[len("test://"):],
+			"mimeType": "text/plain",
+		})
+	}
+
+	result, _ := json.Marshal(map[string]interface{}{
+		"resources": list,
+	})
+	writeResult(conn, msg, result)
+}
+
+func handleResourcesRead(conn *websocket.Conn, msg *Message) {
+	var req struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(msg.Params, &req); err != nil {
+		log.Printf("Failed to unmarshal resources/read request: %v", err)
+		return
+	}
+
+	text, ok := resources[req.URI]
+	if !ok {
+		writeError(conn, msg, -32002, fmt.Sprintf("Resource not found: %s", req.URI))
+		return
+	}
+
+	result, _ := json.Marshal(map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"uri":      req.URI,
+				"mimeType": "text/plain",
+				"text":     text,
+			},
+		},
+	})
+	writeResult(conn, msg, result)
+}
+
+func handlePromptsList(conn *websocket.Conn, msg *Message) {
+	result, _ := json.Marshal(map[string]interface{}{
+		"prompts": []map[string]interface{}{
+			{
+				"name":        "greet",
+				"description": "Greet someone by name",
+				"arguments": []map[string]interface{}{
+					{"name": "name", "description": "Who to greet", "required": true},
+				},
+			},
+		},
+	})
+	writeResult(conn, msg, result)
+}
+
+func handlePromptsGet(conn *websocket.Conn, msg *Message) {
+	var req struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(msg.Params, &req); err != nil {
+		log.Printf("Failed to unmarshal prompts/get request: %v", err)
+		return
+	}
+
+	if req.Name != "greet" {
+		writeError(conn, msg, -32602, fmt.Sprintf("Unknown prompt: %s", req.Name))
+		return
+	}
+
+	result, _ := json.Marshal(map[string]interface{}{
+		"description": "Greeting prompt",
+		"messages": []map[string]interface{}{
+			{
+				"role": "user",
+				"content": map[string]interface{}{
+					"type": "text",
+					"text": fmt.Sprintf("Please greet %s warmly.", req.Arguments["name"]),
+				},
+			},
+		},
+	})
+	writeResult(conn, msg, result)
+}
+
+func sendLogNotification(conn *websocket.Conn, level, data string) {
+	params, _ := json.Marshal(map[string]interface{}{
+		"level":  level,
+		"logger": "test-server",
+		"data":   data,
+	})
+
+	if err := conn.WriteJSON(Message{Method: "notifications/message", Params: params}); err != nil {
+		log.Printf("Failed to send log notification: %v", err)
+	}
+}
+
+func writeResult(conn *websocket.Conn, msg *Message, result json.RawMessage) {
+	responseMsg := Message{
+		ID:     msg.ID,
+		Method: msg.Method,
+		Result: result,
+	}
+
+	if err := conn.WriteJSON(responseMsg); err != nil {
+		log.Printf("Failed to send %s response: %v", msg.Method, err)
+	}
+}
+
+func writeError(conn *websocket.Conn, msg *Message, code int, message string) {
+	responseMsg := Message{
+		ID:     msg.ID,
+		Method: msg.Method,
+		Error:  &Error{Code: code, Message: message},
+	}
+
+	if err := conn.WriteJSON(responseMsg); err != nil {
+		log.Printf("Failed to send %s error: %v", msg.Method, err)
+	}
+}
+
 func handlePing(conn *websocket.Conn, msg *Message) {
 	responseMsg := Message{
 		ID:     msg.ID,